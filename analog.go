@@ -56,19 +56,20 @@ func drawLine(pix Pixels, sx, sy, x0i, y0i int, color tcolor.RGBColor) {
 // ColorState remembers last set foreground/background colors.
 // TODO: move this into ansipixels (optimize setting fg/bg only when needed).
 type ColorState struct {
-	fg, bg tcolor.RGBColor
+	fg, bg       tcolor.RGBColor
+	fgSet, bgSet bool // the terminal colors are unknown (e.g. default, not black) until first set.
 }
 
 func (cs *ColorState) SetFG(ap *ansipixels.AnsiPixels, c tcolor.RGBColor) {
-	if cs.fg != c {
-		cs.fg = c
+	if !cs.fgSet || cs.fg != c {
+		cs.fg, cs.fgSet = c, true
 		ap.WriteString(c.Foreground())
 	}
 }
 
 func (cs *ColorState) SetBG(ap *ansipixels.AnsiPixels, c tcolor.RGBColor) {
-	if cs.bg != c {
-		cs.bg = c
+	if !cs.bgSet || cs.bg != c {
+		cs.bg, cs.bgSet = c, true
 		ap.WriteString(c.Background())
 	}
 }
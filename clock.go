@@ -0,0 +1,54 @@
+// Clock sources: the real time, or a fake/accelerated one (-fake-time and -speed) for demos and tests.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Clock is where the current time comes from.
+type Clock interface {
+	Now() time.Time
+	// Real converts a duration of this clock into the wall clock duration to wait for it.
+	Real(d time.Duration) time.Duration
+}
+
+// RealClock is the system time.
+type RealClock struct{}
+
+func (RealClock) Now() time.Time { return time.Now() }
+
+func (RealClock) Real(d time.Duration) time.Duration { return d }
+
+// FakeClock starts at Start (when created) and runs Speed times faster than the real time.
+type FakeClock struct {
+	Start  time.Time
+	Speed  float64
+	origin time.Time
+}
+
+// NewFakeClock returns a clock starting now at start and going speed times faster.
+func NewFakeClock(start time.Time, speed float64) *FakeClock {
+	return &FakeClock{Start: start, Speed: speed, origin: time.Now()}
+}
+
+func (f *FakeClock) Now() time.Time {
+	return f.Start.Add(time.Duration(float64(time.Since(f.origin)) * f.Speed))
+}
+
+func (f *FakeClock) Real(d time.Duration) time.Duration {
+	return time.Duration(math.Ceil(float64(d) / f.Speed))
+}
+
+// ParseSpeed parses a time speed factor like 60x (or 60, 0.5x).
+func ParseSpeed(speed string) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSuffix(strings.ToLower(speed), "x"), 64)
+	if err != nil || f <= 0 {
+		return 0, fmt.Errorf("invalid speed %q, should be a positive factor like 60x", speed)
+	}
+	return f, nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSpeed(t *testing.T) {
+	tests := []struct {
+		speed   string
+		want    float64
+		wantErr bool
+	}{
+		{"60x", 60, false},
+		{"60X", 60, false},
+		{"60", 60, false},
+		{"0.5x", 0.5, false},
+		{"1x", 1, false},
+		{"0x", 0, true},
+		{"-2x", 0, true},
+		{"x", 0, true},
+		{"fast", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSpeed(tt.speed)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseSpeed(%q) = %v, %v, want %v (error %v)", tt.speed, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2026, 12, 31, 23, 59, 50, 0, time.UTC)
+	tests := []struct {
+		speed   float64
+		elapsed time.Duration // real time since the clock was created.
+		now     time.Time
+		real    time.Duration // wall clock time to wait for 1 minute of this clock.
+	}{
+		{1, 0, start, time.Minute},
+		{1, 3 * time.Second, start.Add(3 * time.Second), time.Minute},
+		{60, 2 * time.Second, start.Add(2 * time.Minute), time.Second},
+		{0.5, 10 * time.Second, start.Add(5 * time.Second), 2 * time.Minute},
+		{7, 0, start, 8571428572}, // rounded up: waiting less would wake up too early.
+	}
+	for _, tt := range tests {
+		f := &FakeClock{Start: start, Speed: tt.speed, origin: time.Now().Add(-tt.elapsed)}
+		if got := f.Now(); got.Sub(tt.now).Abs() > time.Duration(tt.speed*float64(100*time.Millisecond)) {
+			t.Errorf("speed %v after %v: Now() = %v, want %v", tt.speed, tt.elapsed, got, tt.now)
+		}
+		if got := f.Real(time.Minute); got != tt.real {
+			t.Errorf("speed %v: Real(1m) = %v, want %v", tt.speed, got, tt.real)
+		}
+	}
+	if c := NewFakeClock(start, 1); c.Now().Sub(start) > time.Second {
+		t.Errorf("NewFakeClock should start at %v, got %v", start, c.Now())
+	}
+	var r RealClock
+	if r.Real(time.Minute) != time.Minute || time.Since(r.Now()).Abs() > time.Second {
+		t.Errorf("RealClock isn't the system time")
+	}
+}
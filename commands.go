@@ -0,0 +1,227 @@
+// Remote control commands (control socket, HTTP API and signals) applied by the main loop.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"fortio.org/duration"
+	"fortio.org/log"
+	"fortio.org/terminal/ansipixels/tcolor"
+)
+
+// Command is a control request (e.g. "text Break!") sent to the main loop, which replies on Reply.
+type Command struct {
+	Name  string
+	Arg   string
+	Reply chan string
+}
+
+// CommandsHelp lists the commands accepted by Execute.
+const CommandsHelp = "set-countdown <duration or date/time>, text <text>, color <color>, pause, resume, toggle-pause, reset, stop, status, quit"
+
+// ParseCommand splits a command line into the command name and its argument (rest of the line, unquoted).
+func ParseCommand(line string) Command {
+	name, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+	arg = strings.TrimSpace(arg)
+	if len(arg) >= 2 && arg[0] == '"' && arg[len(arg)-1] == '"' {
+		arg = arg[1 : len(arg)-1]
+	}
+	return Command{Name: strings.ToLower(name), Arg: arg, Reply: make(chan string, 1)}
+}
+
+// Timer returns the reference time for countdowns and elapsed time: now, or when paused.
+func (c *Config) Timer(now time.Time) time.Time {
+	if !c.pausedAt.IsZero() {
+		return c.pausedAt
+	}
+	return now
+}
+
+// Pause stops the countdown or elapsed time at now.
+func (c *Config) Pause(now time.Time) {
+	if c.pausedAt.IsZero() {
+		c.pausedAt = now
+	}
+}
+
+// Resume restarts a paused countdown or elapsed time where it was.
+func (c *Config) Resume(now time.Time) {
+	if c.pausedAt.IsZero() {
+		return
+	}
+	paused := now.Sub(c.pausedAt)
+	c.end = c.end.Add(paused)
+	c.start = c.start.Add(paused)
+	c.pausedAt = time.Time{}
+	c.updateCountdownText(now)
+}
+
+// TogglePause pauses or resumes the countdown or elapsed time.
+func (c *Config) TogglePause(now time.Time) {
+	if c.pausedAt.IsZero() {
+		c.Pause(now)
+	} else {
+		c.Resume(now)
+	}
+}
+
+// Reset restarts the countdown (for its initial duration) or the elapsed time.
+func (c *Config) Reset(now time.Time) {
+	if !c.pausedAt.IsZero() {
+		c.pausedAt = now // stays paused, at the start.
+	}
+	c.end = now.Add(c.duration)
+	c.start = now
+	c.updateCountdownText(now)
+}
+
+// SetCountdown starts a countdown for a duration (e.g. 10m) or until a date/time (e.g. "3:05 pm").
+func (c *Config) SetCountdown(now time.Time, arg string) error {
+	var end time.Time
+	if d, err := duration.Parse(arg); err == nil && d > 0 {
+		end = now.Add(d)
+	} else if end, err = duration.ParseDateTime(now, arg); err != nil {
+		return fmt.Errorf("invalid duration or date/time %q", arg)
+	}
+	c.countDown = true
+	c.end = end
+	c.duration = end.Sub(now)
+	c.pausedAt = time.Time{}
+	c.updateCountdownText(now)
+	return nil
+}
+
+// updateCountdownText updates the default text when the countdown target changed.
+func (c *Config) updateCountdownText(now time.Time) {
+	if c.autoText && c.countDown {
+		c.now = now
+		c.text = c.CountdownText()
+	}
+}
+
+// Stop ends the countdown, back to showing the time.
+func (c *Config) Stop() {
+	c.countDown = false
+	c.pausedAt = time.Time{}
+	if c.autoText {
+		c.text = ""
+	}
+}
+
+// SetColor changes the clock color (same syntax as -color).
+func (c *Config) SetColor(arg string) error {
+	color, err := tcolor.FromString(arg)
+	if err != nil {
+		return err
+	}
+	c.breath = false
+	c.color = c.ap.ColorOutput.Foreground(color)
+	c.colorName = arg
+	return nil
+}
+
+// commandState is what the commands can change, compared before and after one to skip
+// the redraw (and state saving) when nothing changed, e.g. for status or a repeated pause.
+type commandState struct {
+	countDown, breath    bool
+	end, start, pausedAt time.Time
+	duration             time.Duration
+	text, color          string
+}
+
+func (c *Config) commandState() commandState {
+	return commandState{
+		countDown: c.countDown, breath: c.breath, end: c.end, start: c.start, pausedAt: c.pausedAt,
+		duration: c.duration, text: c.text, color: c.color,
+	}
+}
+
+// Status is the current mode, time shown and countdown target, as reported by the status command.
+type Status struct {
+	Mode   string    `json:"mode"` // clock, countdown or elapsed.
+	Paused bool      `json:"paused"`
+	Time   string    `json:"time"`
+	Left   string    `json:"left,omitempty"`  // countdown time left.
+	Target time.Time `json:"target,omitzero"` // countdown end.
+	Text   string    `json:"text,omitempty"`
+	Color  string    `json:"color"`
+}
+
+func (c *Config) Status(now time.Time) Status {
+	s := Status{Mode: "clock", Paused: !c.pausedAt.IsZero(), Time: now.Format(c.format), Text: c.text, Color: c.colorName}
+	timer := c.Timer(now)
+	switch {
+	case c.countDown:
+		left := c.end.Sub(timer).Round(time.Second)
+		s.Mode, s.Time, s.Left, s.Target = "countdown", DurationString(left, true), left.String(), c.end
+	case c.countUp:
+		s.Mode, s.Time = "elapsed", DurationString(timer.Sub(c.start), true)
+	}
+	return s
+}
+
+// Execute applies the command and returns the reply ("ok", the status or an error) and whether to quit.
+func (c *Config) Execute(cmd Command) (string, bool) {
+	now := c.clock.Now()
+	log.LogVf("Control command %q %q", cmd.Name, cmd.Arg)
+	switch cmd.Name {
+	case "pause", "resume", "toggle-pause", "reset":
+		if !c.countDown && !c.countUp {
+			return "error: no countdown or elapsed time to " + cmd.Name, false
+		}
+	}
+	var err error
+	switch cmd.Name {
+	case "set-countdown":
+		err = c.SetCountdown(now, cmd.Arg)
+	case "text":
+		c.text = cmd.Arg
+		c.autoText = false
+	case "color":
+		err = c.SetColor(cmd.Arg)
+	case "pause":
+		c.Pause(now)
+	case "resume":
+		c.Resume(now)
+	case "toggle-pause":
+		c.TogglePause(now)
+	case "reset":
+		c.Reset(now)
+	case "stop":
+		c.Stop()
+	case "status":
+		data, _ := json.Marshal(c.Status(now))
+		return string(data), false
+	case "quit":
+		return "ok", true
+	default:
+		err = fmt.Errorf("unknown command %q, should be one of %s", cmd.Name, CommandsHelp)
+	}
+	if err != nil {
+		return "error: " + err.Error(), false
+	}
+	return "ok", false
+}
+
+// QuickControls sends the commands mapped to the quickControls signals (e.g. pkill -USR1 tclock).
+func QuickControls(commands chan<- Command) {
+	if len(quickControls) == 0 {
+		return
+	}
+	sigs := make(chan os.Signal, 1)
+	for s := range quickControls {
+		signal.Notify(sigs, s)
+	}
+	go func() {
+		for s := range sigs {
+			reply := SendCommand(commands, Command{Name: quickControls[s], Reply: make(chan string, 1)})
+			log.LogVf("Signal %v: %s %s", s, quickControls[s], reply)
+		}
+	}()
+}
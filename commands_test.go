@@ -0,0 +1,95 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		line      string
+		name, arg string
+	}{
+		{"status", "status", ""},
+		{"  PAUSE \n", "pause", ""},
+		{"text Break time!", "text", "Break time!"},
+		{`text "  quoted  "`, "text", "  quoted  "},
+		{`text "`, "text", `"`},
+		{"set-countdown   3:05 pm  ", "set-countdown", "3:05 pm"},
+		{"color Red", "color", "Red"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		cmd := ParseCommand(tt.line)
+		if cmd.Name != tt.name || cmd.Arg != tt.arg || cmd.Reply == nil {
+			t.Errorf("ParseCommand(%q) = %q %q, want %q %q", tt.line, cmd.Name, cmd.Arg, tt.name, tt.arg)
+		}
+	}
+}
+
+func TestStatus(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		c    *Config
+		want Status
+	}{
+		{"clock", &Config{format: "15:04:05", colorName: "red"}, Status{Mode: "clock", Time: "12:00:00", Color: "red"}},
+		{
+			"countdown", &Config{countDown: true, end: now.Add(90 * time.Second), text: "tea"},
+			Status{Mode: "countdown", Time: "01:30", Left: "1m30s", Target: now.Add(90 * time.Second), Text: "tea"},
+		},
+		{
+			"paused elapsed", &Config{countUp: true, start: now.Add(-time.Hour), pausedAt: now.Add(-time.Minute)},
+			Status{Mode: "elapsed", Paused: true, Time: "59:00"},
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.c.Status(now); got != tt.want {
+			t.Errorf("%s: Status() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExecuteChanges(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		cmds    []Command
+		changed bool
+	}{
+		{[]Command{{Name: "status"}}, false},
+		{[]Command{{Name: "bogus"}}, false},
+		{[]Command{{Name: "set-countdown", Arg: "not a duration"}}, false},
+		{[]Command{{Name: "resume"}}, false},
+		{[]Command{{Name: "pause"}}, true},
+		{[]Command{{Name: "pause"}, {Name: "pause"}}, false},
+		{[]Command{{Name: "text", Arg: "Break"}}, true},
+		{[]Command{{Name: "set-countdown", Arg: "5m"}}, true},
+	}
+	for _, tt := range tests {
+		c := &Config{clock: NewFakeClock(now, 1), countDown: true, end: now.Add(time.Hour), duration: time.Hour}
+		// The changes of the last command only.
+		for _, cmd := range tt.cmds[:len(tt.cmds)-1] {
+			c.Execute(cmd)
+		}
+		before := c.commandState()
+		c.Execute(tt.cmds[len(tt.cmds)-1])
+		if changed := c.commandState() != before; changed != tt.changed {
+			t.Errorf("%v: changed %v, want %v", tt.cmds, changed, tt.changed)
+		}
+	}
+}
+
+func TestExecuteClock(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, name := range []string{"pause", "resume", "toggle-pause", "reset"} {
+		c := &Config{clock: NewFakeClock(now, 1), format: "15:04"}
+		reply, quit := c.Execute(Command{Name: name})
+		if reply != "error: no countdown or elapsed time to "+name || quit {
+			t.Errorf("%s on the clock: reply %q, quit %v, want an error", name, reply, quit)
+		}
+		if c.commandState() != (commandState{}) || c.Status(now).Paused {
+			t.Errorf("%s on the clock changed it: %+v", name, c.commandState())
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Config file with defaults and named presets of flag values, and `config dump`.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"fortio.org/log"
+)
+
+// ConfigFile is the JSON config file: flag values (by flag name, without the dash) applied
+// by default and named presets selected with -preset. Values can be strings, numbers or booleans.
+//
+//	{
+//	  "defaults": {"24": true},
+//	  "presets": {
+//	    "talk": {"countdown": "45m", "box": true, "color": "orange", "text": "Time left"}
+//	  }
+//	}
+type ConfigFile struct {
+	Defaults map[string]any            `json:"defaults"`
+	Presets  map[string]map[string]any `json:"presets"`
+}
+
+// metaFlags are the flags selecting the configuration, not part of it.
+var metaFlags = []string{"config", "preset"}
+
+// ConfigDir is the tclock directory in the user's config directory (e.g. ~/.config/tclock).
+func ConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tclock"), nil
+}
+
+// DefaultConfigPath is the config file used when -config isn't specified.
+func DefaultConfigPath() string {
+	dir, err := ConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "config.json")
+}
+
+// LoadConfigFile reads the config file. A missing file is only an error if mustExist.
+func LoadConfigFile(path string, mustExist bool) (*ConfigFile, error) {
+	cf := &ConfigFile{}
+	if path == "" {
+		return cf, nil
+	}
+	data, err := os.ReadFile(path)
+	if !mustExist && errors.Is(err, os.ErrNotExist) {
+		return cf, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, cf); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	log.LogVf("Loaded config %s: %d defaults, %d presets", path, len(cf.Defaults), len(cf.Presets))
+	return cf, nil
+}
+
+// ExplicitFlags returns the flags set (on the command line).
+func ExplicitFlags() map[string]bool {
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	return explicit
+}
+
+// setFlags sets the values not already explicitly set (which take precedence).
+func setFlags(values map[string]any, explicit map[string]bool) error {
+	for _, name := range slices.Sorted(maps.Keys(values)) { // sorted so errors are deterministic.
+		if flag.Lookup(name) == nil || slices.Contains(metaFlags, name) {
+			return fmt.Errorf("unknown flag %q", name)
+		}
+		if explicit[name] {
+			continue
+		}
+		if err := flag.Set(name, fmt.Sprint(values[name])); err != nil {
+			return fmt.Errorf("flag %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Apply sets the flags from the defaults and then the named preset (if not empty),
+// unless they are explicitly set.
+func (cf *ConfigFile) Apply(preset string, explicit map[string]bool) error {
+	if err := setFlags(cf.Defaults, explicit); err != nil {
+		return fmt.Errorf("defaults: %w", err)
+	}
+	if preset == "" {
+		return nil
+	}
+	values, found := cf.Presets[preset]
+	if !found {
+		return fmt.Errorf("preset %q not found, available: %s", preset,
+			strings.Join(slices.Sorted(maps.Keys(cf.Presets)), ", "))
+	}
+	if err := setFlags(values, explicit); err != nil {
+		return fmt.Errorf("preset %q: %w", preset, err)
+	}
+	return nil
+}
+
+// isBoolFlag is the same check the flag package does for flags not needing a value.
+func isBoolFlag(f *flag.Flag) bool {
+	bf, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && bf.IsBoolFlag()
+}
+
+// DumpFlags writes the effective configuration, all the flags values, as JSON
+// (usable as a config file preset).
+func DumpFlags(w io.Writer) error {
+	values := make(map[string]any)
+	flag.VisitAll(func(f *flag.Flag) {
+		if slices.Contains(metaFlags, f.Name) {
+			return
+		}
+		if isBoolFlag(f) {
+			values[f.Name] = f.Value.String() == "true"
+			return
+		}
+		values[f.Name] = f.Value.String()
+	})
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
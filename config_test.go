@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Flags for the config and environment tests (the real ones are defined in Main).
+var (
+	testColor = flag.String("test-color", "red", "test only")
+	testBox   = flag.Bool("test-box", false, "test only")
+)
+
+func resetTestFlags() {
+	*testColor, *testBox = "red", false
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.json")
+	if cf, err := LoadConfigFile(missing, false); err != nil || cf.Defaults != nil {
+		t.Errorf("missing optional config: %+v, %v", cf, err)
+	}
+	if _, err := LoadConfigFile(missing, true); err == nil {
+		t.Errorf("missing -config file should be an error")
+	}
+	if _, err := LoadConfigFile(writeConfig(t, "{bad"), false); err == nil {
+		t.Errorf("invalid json should be an error")
+	}
+}
+
+func TestConfigApply(t *testing.T) {
+	path := writeConfig(t, `{
+  "defaults": {"test-color": "blue"},
+  "presets": {"boxed": {"test-box": true, "test-color": "green"}, "bogus": {"no-such-flag": 1}}
+}`)
+	cf, err := LoadConfigFile(path, true)
+	if err != nil {
+		t.Fatalf("LoadConfigFile: %v", err)
+	}
+	tests := []struct {
+		preset   string
+		explicit map[string]bool
+		color    string
+		box      bool
+		err      string
+	}{
+		{"", nil, "blue", false, ""},
+		{"boxed", nil, "green", true, ""},
+		{"boxed", map[string]bool{"test-color": true}, "red", true, ""},
+		{"nope", nil, "blue", false, `preset "nope" not found, available: bogus, boxed`},
+		{"bogus", nil, "blue", false, `preset "bogus": unknown flag "no-such-flag"`},
+	}
+	for _, tt := range tests {
+		resetTestFlags()
+		err := cf.Apply(tt.preset, tt.explicit)
+		if (err == nil) != (tt.err == "") || (err != nil && !strings.Contains(err.Error(), tt.err)) {
+			t.Errorf("preset %q: error %v, want %q", tt.preset, err, tt.err)
+		}
+		if *testColor != tt.color || *testBox != tt.box {
+			t.Errorf("preset %q: color %s, box %v, want %s, %v", tt.preset, *testColor, *testBox, tt.color, tt.box)
+		}
+	}
+	resetTestFlags()
+}
@@ -0,0 +1,69 @@
+// TCLOCK_* environment variables for all the flags.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"fortio.org/struct2env"
+)
+
+// EnvPrefix is the prefix of the environment variables setting the flags, e.g. TCLOCK_COLOR_DISC for -color-disc.
+const EnvPrefix = "TCLOCK_"
+
+// cliFlags are handled by the cli and log packages during the flags parsing (and have their own LOGGER_* variables).
+var cliFlags = []string{"loglevel", "quiet", "logger-force-color", "logger-no-color"}
+
+// EnvName is the environment variable name for the flag (without the prefix).
+func EnvName(flagName string) string {
+	return strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// envFlags calls fn for each flag that can be set from the environment with its variable name.
+func envFlags(fn func(f *flag.Flag, envName string)) {
+	flag.VisitAll(func(f *flag.Flag) {
+		if !slices.Contains(cliFlags, f.Name) {
+			fn(f, EnvPrefix+EnvName(f.Name))
+		}
+	})
+}
+
+// SetFlagsFromEnv sets the flags from the TCLOCK_* environment variables, unless explicitly set.
+func SetFlagsFromEnv(explicit map[string]bool) error {
+	var errs []error
+	envFlags(func(f *flag.Flag, envName string) {
+		value, found := os.LookupEnv(envName)
+		if !found || explicit[f.Name] {
+			return
+		}
+		// flag.Set (vs f.Value.Set) so it also counts as set, for precedence over the config file.
+		if err := flag.Set(f.Name, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s=%q: %w", envName, value, err))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// EnvHelp lists the TCLOCK_* variables with their current values (for tclock envhelp).
+func EnvHelp(w io.Writer) {
+	var kvl []struct2env.KeyValue
+	envFlags(func(f *flag.Flag, envName string) {
+		value, found := os.LookupEnv(envName)
+		if !found {
+			value = f.DefValue
+		}
+		quoted, err := struct2env.ShellQuote(value)
+		if err != nil {
+			return
+		}
+		kvl = append(kvl, struct2env.KeyValue{Key: EnvName(f.Name), ShellQuotedVal: quoted})
+	})
+	fmt.Fprintln(w, "# tclock flags environment variables:")
+	fmt.Fprint(w, struct2env.ToShellWithPrefix(EnvPrefix, kvl, true))
+}
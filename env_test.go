@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestEnvName(t *testing.T) {
+	tests := []struct{ flag, want string }{
+		{"color", "COLOR"},
+		{"color-disc", "COLOR_DISC"},
+		{"24", "24"},
+		{"no-seconds", "NO_SECONDS"},
+	}
+	for _, tt := range tests {
+		if got := EnvName(tt.flag); got != tt.want {
+			t.Errorf("EnvName(%q) = %q, want %q", tt.flag, got, tt.want)
+		}
+	}
+}
+
+func TestSetFlagsFromEnv(t *testing.T) {
+	tests := []struct {
+		name       string
+		color, box string // TCLOCK_ environment values, "" for unset.
+		explicit   map[string]bool
+		wantColor  string
+		wantBox    bool
+		wantErr    bool
+	}{
+		{"unset", "", "", nil, "red", false, false},
+		{"set", "blue", "true", nil, "blue", true, false},
+		{"explicit flag wins", "blue", "", map[string]bool{"test-color": true}, "red", false, false},
+		{"invalid", "", "maybe", nil, "red", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetTestFlags()
+			if tt.color != "" {
+				t.Setenv("TCLOCK_TEST_COLOR", tt.color)
+			}
+			if tt.box != "" {
+				t.Setenv("TCLOCK_TEST_BOX", tt.box)
+			}
+			err := SetFlagsFromEnv(tt.explicit)
+			if (err != nil) != tt.wantErr || *testColor != tt.wantColor || *testBox != tt.wantBox {
+				t.Errorf("color %s, box %v, error %v", *testColor, *testBox, err)
+			}
+		})
+	}
+	resetTestFlags()
+}
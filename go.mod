@@ -6,12 +6,13 @@ require (
 	fortio.org/cli v1.12.3
 	fortio.org/duration v1.0.4
 	fortio.org/log v1.18.3
+	fortio.org/struct2env v0.4.2
 	fortio.org/terminal v0.63.4
+	golang.org/x/term v0.39.0
 )
 
 require (
 	fortio.org/safecast v1.2.0 // indirect
-	fortio.org/struct2env v0.4.2 // indirect
 	fortio.org/version v1.0.4 // indirect
 	github.com/jbuchbinder/gopnm v0.0.0-20220507095634-e31f54490ce0 // indirect
 	github.com/kortschak/goroutine v1.1.3 // indirect
@@ -19,5 +20,4 @@ require (
 	golang.org/x/crypto/x509roots/fallback v0.0.0-20250406160420-959f8f3db0fb // indirect
 	golang.org/x/image v0.35.0 // indirect
 	golang.org/x/sys v0.40.0 // indirect
-	golang.org/x/term v0.39.0 // indirect
 )
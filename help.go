@@ -0,0 +1,97 @@
+// In app help overlay: key bindings and current mode.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fortio.org/terminal/ansipixels/tcolor"
+)
+
+// HelpLines lists the active key bindings (depending on the mode) followed by the current settings.
+func (c *Config) HelpLines() []string {
+	key := func(action Action, desc string) string {
+		return fmt.Sprintf("  %-13s %s", c.keys.Keys(action), desc)
+	}
+	quit := "quit"
+	switch {
+	case c.countDown:
+		quit += " (aborts the countdown)"
+	case c.cmd != nil:
+		quit += " (aborts the command)"
+	}
+	lines := []string{
+		"Keys:",
+		key(AnalogAction, "toggle analog / anti aliased analog"),
+		key(ContinuousAction, "toggle continuous updates"),
+		key(HelpAction, "toggle this help"),
+		key(QuitAction, quit),
+	}
+	if c.tail != nil {
+		lines = append(lines,
+			"  space or p    pause/resume following the output",
+			"  PgUp/PgDn     scroll back (also arrows and mouse wheel)",
+			"  /             search, then n/N for the previous/next match",
+			"  Esc or End    resume following")
+	} else if c.position == MousePosition && c.bounceSpeed <= 0 {
+		lines = append(lines,
+			"Mouse:",
+			"  click         place the clock, click again to follow the mouse",
+			"  drag          move the placed clock")
+		if c.colorDisc != (tcolor.RGBColor{}) && !c.analog && !c.aa {
+			lines = append(lines, "  wheel         change the disc radius")
+		}
+		lines = append(lines, "  right click   cycle the color")
+	}
+	mode := "digital"
+	switch {
+	case c.aa:
+		mode = "anti aliased analog"
+	case c.analog:
+		mode = "analog"
+	}
+	if c.continuous {
+		mode += ", continuous"
+	}
+	lines = append(lines, "", "Mode: "+mode)
+	if c.countDown {
+		lines = append(lines, "Countdown to: "+c.end.Format("2006-01-02 "+c.format))
+	}
+	colors := "Color: " + c.colorName
+	if c.colorDisc != (tcolor.RGBColor{}) {
+		colors += fmt.Sprintf(", disc: %02X%02X%02X (radius %.1f)", c.colorDisc.R, c.colorDisc.G, c.colorDisc.B, c.radius)
+	}
+	return append(lines, colors)
+}
+
+// DrawHelp draws the help overlay box in the middle of the screen.
+func (c *Config) DrawHelp() {
+	lines := c.HelpLines()
+	width := 0
+	for _, line := range lines {
+		width = max(width, c.ap.ScreenWidth(line))
+	}
+	w, h := min(width+4, c.ap.W), min(len(lines)+2, c.ap.H)
+	x, y := (c.ap.W-w)/2, (c.ap.H-h)/2
+	c.ap.DrawRoundBox(x, y, w, h)
+	for i, line := range lines[:h-2] {
+		line = truncate(line, w-4)
+		c.ap.WriteAtStr(x+1, y+1+i, " "+line+strings.Repeat(" ", w-3-c.ap.ScreenWidth(line)))
+	}
+}
+
+// ToggleHelp shows or hides the help overlay. In tail mode the output is held (but still kept
+// in the scrollback) while the help is shown and the tail area is redrawn when it's hidden.
+func (c *Config) ToggleHelp() {
+	c.help = !c.help
+	if c.tail == nil {
+		return
+	}
+	if c.help {
+		c.tailOut.Hold = true
+		return
+	}
+	c.tailOut.Hold = c.view.paused
+	_ = c.ap.OnResize()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHelpLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(c *Config)
+		want     []string
+		notWants []string
+	}{
+		{
+			"default", func(*Config) {},
+			[]string{"  q or Ctrl-C   quit", "  wheel         change the disc radius", "Mode: digital",
+				"Color: red, disc: E0C020 (radius 1.2)"},
+			[]string{"  space or p    pause/resume following the output"},
+		},
+		{
+			"remapped keys", func(c *Config) { _ = c.keys.Parse("quit=x,analog=") },
+			[]string{"  x             quit", "  (disabled)    toggle analog / anti aliased analog"},
+			nil,
+		},
+		{
+			"aa countdown", func(c *Config) {
+				c.aa, c.continuous, c.countDown = true, true, true
+				c.end = time.Date(2026, 1, 1, 13, 0, 0, 0, time.UTC)
+			},
+			[]string{"  q or Ctrl-C   quit (aborts the countdown)", "Mode: anti aliased analog, continuous",
+				"Countdown to: 2026-01-01 1:00:00"},
+			[]string{"  wheel         change the disc radius"},
+		},
+		{
+			"tail", func(c *Config) { c.tail = strings.NewReader("") },
+			[]string{"  space or p    pause/resume following the output"},
+			[]string{"Mouse:"},
+		},
+	}
+	for _, tt := range tests {
+		c := testConfig()
+		c.keys = DefaultKeyMap()
+		tt.setup(c)
+		lines := c.HelpLines()
+		for _, want := range tt.want {
+			if !slices.Contains(lines, want) {
+				t.Errorf("%s: missing %q in:\n%s", tt.name, want, strings.Join(lines, "\n"))
+			}
+		}
+		for _, notWant := range tt.notWants {
+			if slices.Contains(lines, notWant) {
+				t.Errorf("%s: unexpected %q in:\n%s", tt.name, notWant, strings.Join(lines, "\n"))
+			}
+		}
+	}
+}
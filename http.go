@@ -0,0 +1,132 @@
+// Embedded HTTP control and status API (-http) with a page mirroring the clock.
+
+package main
+
+import (
+	"encoding/json"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"fortio.org/log"
+)
+
+// mirrorPage polls the status API and shows the time and text, big, in the browser.
+const mirrorPage = `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1">
+<title>tclock</title>
+<style>
+body { background: #000; color: #e02020; font-family: monospace; text-align: center; margin: 0; }
+#time { font-size: 20vw; margin-top: 10vh; }
+#text { font-size: 5vw; color: #ccc; }
+.paused { opacity: 0.5; }
+form { margin-top: 5vh; }
+input, button { font-size: 1.2em; margin: 0.2em; }
+</style></head>
+<body>
+<div id="time">--:--</div>
+<div id="text"></div>
+<form id="ctl">
+<select id="command">
+<option>set-countdown</option><option>text</option><option>color</option>
+<option>pause</option><option>resume</option><option>reset</option><option>stop</option>
+</select>
+<input id="arg" placeholder="10m, text or color">
+<button>Send</button>
+</form>
+<script>
+const colors = {red: "#e02020", green: "#20c020", blue: "#4060ff", yellow: "#e0e020", orange: "#ff9020",
+  purple: "#c040c0", cyan: "#20c0c0", white: "#fff", gray: "#aaa"};
+async function update() {
+  try {
+    const s = await (await fetch("api/status")).json();
+    const t = document.getElementById("time");
+    t.textContent = s.time;
+    t.className = s.paused ? "paused" : "";
+    t.style.color = colors[s.color] || (/^[0-9a-f]{6}$/i.test(s.color) ? "#" + s.color : "");
+    document.getElementById("text").textContent = s.text || "";
+  } catch (e) {
+    document.getElementById("time").textContent = "--:--";
+  }
+}
+document.getElementById("ctl").onsubmit = async (e) => {
+  e.preventDefault();
+  await fetch("api", {method: "POST", headers: {"Content-Type": "application/json"}, body: JSON.stringify({
+    command: document.getElementById("command").value, arg: document.getElementById("arg").value})});
+  update();
+};
+setInterval(update, 500);
+update();
+</script>
+</body></html>
+`
+
+// apiRequest is the POST /api JSON body.
+type apiRequest struct {
+	Command string `json:"command"`
+	Arg     string `json:"arg"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+// replyJSON writes the command reply: the status JSON as is, ok as {"result":"ok"} and errors with a 400.
+func replyJSON(w http.ResponseWriter, reply string) {
+	switch {
+	case strings.HasPrefix(reply, "{"):
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(reply + "\n"))
+	case strings.HasPrefix(reply, "error: "):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": strings.TrimPrefix(reply, "error: ")})
+	default:
+		writeJSON(w, http.StatusOK, map[string]string{"result": reply})
+	}
+}
+
+// HTTPHandler serves the mirror page on /, GET /api/status and POST /api to run a command
+// ({"command": "set-countdown", "arg": "10m"}). There is no authentication but POST /api only accepts JSON,
+// which browsers don't send cross-origin without a CORS preflight (that we don't allow), so other web pages
+// can't send commands.
+func HTTPHandler(commands chan<- Command) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(mirrorPage))
+	})
+	mux.HandleFunc("GET /api/status", func(w http.ResponseWriter, _ *http.Request) {
+		replyJSON(w, SendCommand(commands, Command{Name: "status", Reply: make(chan string, 1)}))
+	})
+	mux.HandleFunc("POST /api", func(w http.ResponseWriter, r *http.Request) {
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+			writeJSON(w, http.StatusUnsupportedMediaType, map[string]string{"error": "Content-Type must be application/json"})
+			return
+		}
+		var req apiRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		cmd := Command{Name: strings.ToLower(req.Command), Arg: req.Arg, Reply: make(chan string, 1)}
+		replyJSON(w, SendCommand(commands, cmd))
+	})
+	return mux
+}
+
+// ListenHTTP starts serving the HTTP API on addr (e.g. :8080) in the background.
+func ListenHTTP(addr string, commands chan<- Command) (net.Listener, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	log.LogVf("Serving the HTTP API on %s", listener.Addr())
+	server := &http.Server{Handler: HTTPHandler(commands), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	return listener, nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// executeLoop executes the commands on c, like the main loop does, until done is closed.
+func executeLoop(c *Config, commands <-chan Command, done <-chan struct{}) {
+	for {
+		select {
+		case cmd := <-commands:
+			reply, _ := c.Execute(cmd)
+			cmd.Reply <- reply
+		case <-done:
+			return
+		}
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := &Config{clock: NewFakeClock(now, 1), format: "15:04", colorName: "red"}
+	commands := make(chan Command)
+	done := make(chan struct{})
+	defer close(done)
+	go executeLoop(c, commands, done)
+	handler := HTTPHandler(commands)
+	tests := []struct {
+		method, path, contentType, body string
+		status                          int
+		want                            string
+	}{
+		{"GET", "/", "", "", http.StatusOK, "<title>tclock</title>"},
+		{"GET", "/api/status", "", "", http.StatusOK, `{"mode":"clock","paused":false,"time":"12:00","color":"red"}`},
+		{"POST", "/api", "application/json", `{"command":"text","arg":"Break"}`, http.StatusOK, `{"result":"ok"}`},
+		{"POST", "/api", "application/json", `{"command":"pause"}`, http.StatusBadRequest, `{"error":"no countdown`},
+		{"POST", "/api", "application/json", `{"command":"set-countdown","arg":"10m"}`, http.StatusOK, `{"result":"ok"}`},
+		{"POST", "/api", "application/json; charset=utf-8", `{"command":"PAUSE"}`, http.StatusOK, `{"result":"ok"}`},
+		// No form (or text/plain) posts: they don't need a CORS preflight, so any web page could send them.
+		{"POST", "/api", "application/x-www-form-urlencoded", "command=quit", http.StatusUnsupportedMediaType, `{"error":`},
+		{"POST", "/api", "text/plain", `{"command":"quit"}`, http.StatusUnsupportedMediaType, `{"error":`},
+		{"POST", "/api", "", `{"command":"quit"}`, http.StatusUnsupportedMediaType, `{"error":`},
+		{"GET", "/api/status", "", "", http.StatusOK, `"mode":"countdown","paused":true,"time":"10:00"`},
+		{"POST", "/api", "application/json", `{"command":"bogus"}`, http.StatusBadRequest, `{"error":"unknown command \"bogus\"`},
+		{"POST", "/api", "application/json", `{bad`, http.StatusBadRequest, `{"error":`},
+		{"GET", "/api", "", "", http.StatusMethodNotAllowed, ""},
+		{"GET", "/nope", "", "", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status || !strings.Contains(rec.Body.String(), tt.want) {
+			t.Errorf("%s %s %s: %d %q, want %d %q", tt.method, tt.path, tt.body, rec.Code, rec.Body.String(), tt.status, tt.want)
+		}
+	}
+}
@@ -25,14 +25,21 @@ func coords(maxV, timeValue float64, radius float64) (float64, float64) {
 	return point(angle(maxV, timeValue), radius)
 }
 
-func (c *Config) DrawImage(now time.Time, seconds bool) {
-	r := min(float64(c.ap.W)/2, float64(c.ap.H)) - 1
-	cxf := float64(c.ap.W) / 2
-	cyf := float64(c.ap.H)
-	cx := int(cxf)
-	cy := int(cyf / 2)
+// ClockImage draws the antialiased clock face and hands, in a pw x ph pixels image, with lines
+// lineWidth pixels wide (1 for the terminal).
+func (c *Config) ClockImage(pw, ph int, now time.Time, seconds bool, lineWidth float64) *image.NRGBA {
+	r := min(float64(pw), float64(ph))/2 - max(1, 2*lineWidth-1) // room for the thick markers.
+	cxf := float64(pw) / 2
+	cyf := float64(ph) / 2
 	// new NRGBA image of the right size
-	img := image.NewNRGBA(image.Rect(0, 0, c.ap.W, 2*c.ap.H))
+	img := image.NewNRGBA(image.Rect(0, 0, pw, ph))
+	line := func(x0, y0, x1, y1 float64, color color.NRGBA) {
+		if lineWidth <= 1 {
+			ansipixels.DrawAALine(img, cxf+x0, cyf+y0, cxf+x1, cyf+y1, color)
+			return
+		}
+		drawThickLine(img, cxf+x0, cyf+y0, cxf+x1, cyf+y1, lineWidth, color)
+	}
 	sec, minute, hour := float64(now.Second()), float64(now.Minute()), now.Hour()
 	if c.continuous {
 		sec = math.Mod(float64(now.UnixMicro())/1e6, 60)
@@ -50,20 +57,61 @@ func (c *Config) DrawImage(now time.Time, seconds bool) {
 			if n%5 == 0 {
 				color = hourDotColor
 			}
-			nx1, ny1 := coords(60, float64(n), r-1.5)
-			nx2, ny2 := coords(60, float64(n), r+0.5)
-			ansipixels.DrawAALine(img, cxf+nx1, cyf+ny1, cxf+nx2, cyf+ny2, color)
+			nx1, ny1 := coords(60, float64(n), r-1.5*lineWidth)
+			nx2, ny2 := coords(60, float64(n), r+0.5*lineWidth)
+			line(nx1, ny1, nx2, ny2, color)
 		}
-		ansipixels.DrawAALine(img, cxf, cyf, cxf+sx, cyf+sy, color.NRGBA{R: 0x50, G: 0x80, B: 0x50, A: 255})
+		line(0, 0, sx, sy, color.NRGBA{R: 0x50, G: 0x80, B: 0x50, A: 255})
 	}
-	ansipixels.DrawAALine(img, cxf, cyf, cxf+mx, cyf+my, color.NRGBA{R: 0x2C, G: 0x59, B: 0xD4, A: 255}) // #2C59D4
-	ansipixels.DrawAALine(img, cxf, cyf, cxf+hx, cyf+hy, color.NRGBA{R: 255, G: 0xA7, B: 10, A: 255})
+	line(0, 0, mx, my, color.NRGBA{R: 0x2C, G: 0x59, B: 0xD4, A: 255}) // #2C59D4
+	line(0, 0, hx, hy, color.NRGBA{R: 255, G: 0xA7, B: 10, A: 255})
+	return img
+}
+
+// drawThickLine draws an antialiased line of the given width, blending over what is already drawn.
+func drawThickLine(img *image.NRGBA, x0, y0, x1, y1, width float64, c color.NRGBA) {
+	half := width / 2
+	b := img.Bounds()
+	minX, maxX := max(b.Min.X, int(math.Floor(min(x0, x1)-half-1))), min(b.Max.X, int(math.Ceil(max(x0, x1)+half+1)))
+	minY, maxY := max(b.Min.Y, int(math.Floor(min(y0, y1)-half-1))), min(b.Max.Y, int(math.Ceil(max(y0, y1)+half+1)))
+	dx, dy := x1-x0, y1-y0
+	length2 := dx*dx + dy*dy
+	for y := minY; y < maxY; y++ {
+		for x := minX; x < maxX; x++ {
+			// distance from the pixel center to the segment.
+			px, py := float64(x)+0.5-x0, float64(y)+0.5-y0
+			t := 0.
+			if length2 > 0 {
+				t = min(1, max(0, (px*dx+py*dy)/length2))
+			}
+			coverage := min(1, max(0, half+0.5-math.Hypot(px-t*dx, py-t*dy)))
+			if coverage == 0 {
+				continue
+			}
+			sa := float64(c.A) / 255 * coverage
+			p := img.NRGBAAt(x, y)
+			da := float64(p.A) / 255
+			oa := sa + da*(1-sa)
+			blend := func(s, d uint8) uint8 {
+				return uint8(math.Round((float64(s)*sa + float64(d)*da*(1-sa)) / oa))
+			}
+			img.SetNRGBA(x, y, color.NRGBA{R: blend(c.R, p.R), G: blend(c.G, p.G), B: blend(c.B, p.B), A: uint8(math.Round(oa * 255))})
+		}
+	}
+}
+
+// DrawImage draws the antialiased clock in the w x h area at ax, ay.
+func (c *Config) DrawImage(ax, ay, w, h int, now time.Time, seconds bool) {
+	r := min(float64(w)/2, float64(h)) - 1
+	cx := ax + w/2
+	cy := ay + h/2
+	img := c.ClockImage(w, 2*h, now, seconds, 1)
 
 	// back to RGBA for drawing
 	dst := image.NewRGBA(img.Bounds())
 	draw.Draw(dst, img.Bounds(), img, image.Point{}, draw.Src)
 	// draw the image at the center of the available space
-	_ = c.ap.ShowScaledImage(dst)
+	_ = c.showImageAt(ax, ay, dst)
 	if !seconds {
 		// Numbers for hours:
 		c.ap.WriteString(tcolor.Reset)
@@ -77,3 +125,19 @@ func (c *Config) DrawImage(now time.Time, seconds bool) {
 		}
 	}
 }
+
+// showImageAt is like ap.ShowScaledImage but at the given position instead of the margin.
+func (c *Config) showImageAt(sx, sy int, img *image.RGBA) error {
+	ap := c.ap
+	if ap.Gray {
+		ansipixels.ToGray(img, img)
+	}
+	switch {
+	case ap.TrueColor:
+		return ap.DrawTrueColorImage(sx, sy, img)
+	case ap.Color256:
+		return ap.Draw216ColorImage(sx, sy, img)
+	default:
+		return ap.DrawMonoImage(sx, sy, ansipixels.GrayScaleImage(img), ap.MonoColor.Foreground())
+	}
+}
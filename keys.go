@@ -0,0 +1,128 @@
+// Configurable key bindings for the main loop actions.
+
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type Action int
+
+const (
+	NoAction Action = iota
+	QuitAction
+	AnalogAction
+	ContinuousAction
+	HelpAction
+)
+
+var actionNames = []string{"none", "quit", "analog", "continuous", "help"}
+
+func (a Action) String() string {
+	return actionNames[a]
+}
+
+// TailKeys are the keys used by the tail mode scrollback (space, p, /, n, N and Esc), handled
+// before the KeyMap so they can't be bound to actions when tailing (see CheckTailKeys).
+const TailKeys = " p/nN\x1b"
+
+// KeyMap maps the first byte of the input to the Action it triggers.
+type KeyMap map[byte]Action
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		'q': QuitAction, 3: QuitAction,
+		'a': AnalogAction, 'A': AnalogAction,
+		'c': ContinuousAction, 'C': ContinuousAction,
+		'?': HelpAction, 'h': HelpAction, 'H': HelpAction,
+	}
+}
+
+// Parse applies bindings like "quit=x,analog=mM": each character of the value is a key
+// (^C for Ctrl-C), replacing the action's previous keys. An empty value disables the action.
+func (km KeyMap) Parse(bindings string) error {
+	for binding := range strings.SplitSeq(bindings, ",") {
+		binding = strings.TrimSpace(binding)
+		if binding == "" {
+			continue
+		}
+		name, keys, found := strings.Cut(binding, "=")
+		idx := slices.Index(actionNames, strings.TrimSpace(name))
+		if !found || idx <= 0 {
+			return fmt.Errorf("invalid key binding %q, should be action=keys with action one of %s",
+				binding, strings.Join(actionNames[1:], ", "))
+		}
+		action := Action(idx)
+		for k, a := range km {
+			if a == action {
+				delete(km, k)
+			}
+		}
+		for i := 0; i < len(keys); i++ {
+			k := keys[i]
+			if k == '^' && i+1 < len(keys) {
+				i++
+				k = keys[i] & 0x1f // ^C is 3, etc.
+			}
+			km[k] = action
+		}
+	}
+	return nil
+}
+
+// CheckTailKeys returns an error if one of the TailKeys is bound to an action (in tail mode).
+func (km KeyMap) CheckTailKeys() error {
+	for i := range len(TailKeys) {
+		if a, found := km[TailKeys[i]]; found && a != NoAction {
+			return fmt.Errorf("key %s is reserved for the tail mode scrollback (space, p, /, n, N and Esc)", keyName(TailKeys[i]))
+		}
+	}
+	return nil
+}
+
+func keyName(k byte) string {
+	switch {
+	case k == ' ':
+		return "space"
+	case k == 27:
+		return "Esc"
+	case k < ' ':
+		return "Ctrl-" + string(rune(k+'@'))
+	default:
+		return string(rune(k))
+	}
+}
+
+// Keys describes the keys bound to the action, e.g. "q or Ctrl-C" (uppercase variants
+// of bound lowercase letters are omitted).
+func (km KeyMap) Keys(action Action) string {
+	var keys []byte
+	for k, a := range km {
+		if a == action {
+			keys = append(keys, k)
+		}
+	}
+	slices.SortFunc(keys, func(a, b byte) int {
+		// printable keys first.
+		if (a < ' ') != (b < ' ') {
+			if a < ' ' {
+				return 1
+			}
+			return -1
+		}
+		return int(a) - int(b)
+	})
+	var names []string
+	for _, k := range keys {
+		if k >= 'A' && k <= 'Z' && km[k+'a'-'A'] == action {
+			continue
+		}
+		names = append(names, keyName(k))
+	}
+	if len(names) == 0 {
+		return "(disabled)"
+	}
+	return strings.Join(names, " or ")
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestKeyMapParse(t *testing.T) {
+	tests := []struct {
+		bindings string
+		changes  KeyMap // expected differences from the DefaultKeyMap (NoAction: removed).
+		wantErr  bool
+	}{
+		{"", KeyMap{}, false},
+		{"quit=x", KeyMap{'x': QuitAction, 'q': NoAction, 3: NoAction}, false},
+		{"quit=x^C", KeyMap{'x': QuitAction, 'q': NoAction}, false},
+		{"quit=x,analog=mM", KeyMap{'x': QuitAction, 'q': NoAction, 3: NoAction,
+			'm': AnalogAction, 'M': AnalogAction, 'a': NoAction, 'A': NoAction}, false},
+		{" help=? , ", KeyMap{'h': NoAction, 'H': NoAction}, false},
+		{"quit=", KeyMap{'q': NoAction, 3: NoAction}, false},
+		{"continuous=a", KeyMap{'a': ContinuousAction, 'c': NoAction, 'C': NoAction}, false},
+		{"bogus=x", nil, true},
+		{"quit", nil, true},
+		{"none=x", nil, true},
+		// The TailKeys are only reserved when tailing, see CheckTailKeys.
+		{"quit=n", KeyMap{'n': QuitAction, 'q': NoAction, 3: NoAction}, false},
+	}
+	for _, tt := range tests {
+		km := DefaultKeyMap()
+		err := km.Parse(tt.bindings)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error %v, want error %v", tt.bindings, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		want := DefaultKeyMap()
+		for k, a := range tt.changes {
+			if a == NoAction {
+				delete(want, k)
+			} else {
+				want[k] = a
+			}
+		}
+		if !maps.Equal(km, want) {
+			t.Errorf("Parse(%q) = %v, want %v", tt.bindings, km, want)
+		}
+	}
+}
+
+func TestCheckTailKeys(t *testing.T) {
+	tests := []struct {
+		bindings string
+		wantErr  bool
+	}{
+		{"", false},
+		{"quit=x,help=", false},
+		{"quit=n", true},
+		{"analog=p", true},
+		{"help=/", true},
+		{"quit=^[", true},
+	}
+	for _, tt := range tests {
+		km := DefaultKeyMap()
+		if err := km.Parse(tt.bindings); err != nil {
+			t.Fatalf("Parse(%q): %v", tt.bindings, err)
+		}
+		if err := km.CheckTailKeys(); (err != nil) != tt.wantErr {
+			t.Errorf("CheckTailKeys() after %q = %v, want error %v", tt.bindings, err, tt.wantErr)
+		}
+	}
+}
+
+func TestKeyMapKeys(t *testing.T) {
+	km := DefaultKeyMap()
+	tests := []struct {
+		action Action
+		want   string
+	}{
+		{QuitAction, "q or Ctrl-C"},
+		{AnalogAction, "a"},
+		{HelpAction, "? or h"},
+		{NoAction, "(disabled)"},
+	}
+	for _, tt := range tests {
+		if got := km.Keys(tt.action); got != tt.want {
+			t.Errorf("Keys(%v) = %q, want %q", tt.action, got, tt.want)
+		}
+	}
+	if err := km.Parse("quit=,analog=A"); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := km.Keys(QuitAction); got != "(disabled)" {
+		t.Errorf("Keys(quit) = %q, want (disabled)", got)
+	}
+	if got := km.Keys(AnalogAction); got != "A" {
+		t.Errorf("Keys(analog) = %q, want A", got)
+	}
+}
@@ -0,0 +1,53 @@
+// Kiosk (locked) mode for wall displays: no keyboard, mouse nor Ctrl-C.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// resizeSettle is how long to wait after the last resize before redrawing in kiosk mode,
+// so a storm of SIGWINCH only redraws once.
+const resizeSettle = 250 * time.Millisecond
+
+// Kiosk ignores all the input except for the (optional) Exit sequence.
+type Kiosk struct {
+	Exit    []byte
+	typed   []byte    // last len(Exit) bytes typed.
+	resized time.Time // last resize not yet redrawn.
+}
+
+// Lock ignores SIGINT (SIGTERM still exits cleanly). To be called after the terminal is opened.
+func (k *Kiosk) Lock() {
+	signal.Ignore(os.Interrupt)
+}
+
+// Input consumes the typed data and returns true if the exit sequence was entered.
+func (k *Kiosk) Input(data []byte) bool {
+	if len(k.Exit) == 0 || len(data) == 0 {
+		return false
+	}
+	k.typed = append(k.typed, data...)
+	if len(k.typed) > len(k.Exit) {
+		k.typed = k.typed[len(k.typed)-len(k.Exit):]
+	}
+	return bytes.Equal(k.typed, k.Exit)
+}
+
+// Resized records a resize, redrawn later by Settled.
+func (k *Kiosk) Resized() error {
+	k.resized = time.Now()
+	return nil
+}
+
+// Settled returns true (once) when the size stopped changing for long enough to redraw.
+func (k *Kiosk) Settled(now time.Time) bool {
+	if k.resized.IsZero() || now.Sub(k.resized) < resizeSettle {
+		return false
+	}
+	k.resized = time.Time{}
+	return true
+}
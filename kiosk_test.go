@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKioskInput(t *testing.T) {
+	tests := []struct {
+		exit   string
+		inputs []string
+		want   bool // for the last input.
+	}{
+		{"", []string{"q"}, false},
+		{"unlock", []string{"unlock"}, true},
+		{"unlock", []string{"un", "lo", "ck"}, true},
+		{"unlock", []string{"xxunl", "ock"}, true},
+		{"unlock", []string{"unlockx"}, false},
+		{"unlock", []string{"q", "\x03"}, false},
+		{"\x1b\x1b", []string{"\x1b", "\x1b"}, true},
+	}
+	for _, tt := range tests {
+		k := &Kiosk{Exit: []byte(tt.exit)}
+		var got bool
+		for _, in := range tt.inputs {
+			got = k.Input([]byte(in))
+		}
+		if got != tt.want {
+			t.Errorf("exit %q, inputs %q: %v, want %v", tt.exit, tt.inputs, got, tt.want)
+		}
+	}
+}
+
+func TestKioskSettled(t *testing.T) {
+	k := &Kiosk{}
+	now := time.Now()
+	if k.Settled(now) {
+		t.Errorf("settled without resize")
+	}
+	_ = k.Resized()
+	if k.Settled(time.Now()) {
+		t.Errorf("settled right after a resize")
+	}
+	later := time.Now().Add(resizeSettle)
+	if !k.Settled(later) {
+		t.Errorf("not settled after %v", resizeSettle)
+	}
+	if k.Settled(later) {
+		t.Errorf("settled twice for one resize")
+	}
+}
@@ -0,0 +1,88 @@
+// Mouse interactions in normal mode: place, drag, resize and recolor the clock.
+
+package main
+
+import (
+	"strings"
+
+	"fortio.org/log"
+	"fortio.org/terminal/ansipixels/tcolor"
+)
+
+// Drag is the in progress drag of the clock: offset from the mouse to the clock position,
+// armed on press and only moving the clock once the mouse moved (otherwise it's a click).
+type Drag struct {
+	active bool
+	moved  bool
+	dx, dy int
+}
+
+const (
+	minRadius  = 0.5
+	maxRadius  = 4.0
+	radiusStep = 0.1
+)
+
+// onClock is true if the (1,1 origin) mouse position is on the last drawn clock.
+func (c *Config) onClock(mx, my int) bool {
+	x, y := mx-1, my-1
+	return x >= c.boxX && x < c.boxX+c.boxW && y >= c.boxY && y < c.boxY+c.boxH
+}
+
+// Mouse handles the mouse events for the clock at x, y (1,1 origin, bottom right corner):
+// a click places it (or switches back to following the mouse), pressing on the placed clock
+// and moving drags it until released, the wheel changes the disc radius (of the digital clock,
+// when there is a disc) and a right click cycles the color.
+// Returns the new position and whether a redraw is needed.
+func (c *Config) Mouse(x, y int) (int, int, bool) {
+	ap := c.ap
+	if !ap.Mouse {
+		return x, y, false
+	}
+	hasDisc := c.colorDisc != (tcolor.RGBColor{}) && !c.analog && !c.aa
+	switch {
+	case (ap.MouseWheelUp() || ap.MouseWheelDown()) && !hasDisc:
+		return x, y, false
+	case ap.MouseWheelUp():
+		c.radius = min(c.radius+radiusStep, maxRadius)
+	case ap.MouseWheelDown():
+		c.radius = max(c.radius-radiusStep, minRadius)
+	case ap.RightClick() && !ap.MouseRelease():
+		c.NextColor()
+	case ap.LeftClick() && !ap.MouseRelease():
+		if !c.trackMouse && c.onClock(ap.Mx, ap.My) {
+			c.drag = Drag{active: true, dx: x - ap.Mx, dy: y - ap.My}
+		}
+		return x, y, false
+	case ap.LeftDrag() && c.drag.active:
+		c.drag.moved = true
+		x = min(max(ap.Mx+c.drag.dx, c.boxW), ap.W)
+		y = min(max(ap.My+c.drag.dy, c.boxH), ap.H)
+	case ap.LeftClick() && ap.MouseRelease():
+		drag := c.drag
+		c.drag = Drag{}
+		if drag.moved {
+			c.Place(true, x, y)
+			return x, y, false
+		}
+		// Click to place the time at the mouse position (or switch back to move with mouse).
+		c.trackMouse = !c.trackMouse
+		c.Place(!c.trackMouse, ap.Mx, ap.My)
+		return x, y, false
+	default:
+		return x, y, false
+	}
+	return x, y, true
+}
+
+// NextColor switches the clock to the next basic color (skipping none and black).
+func (c *Config) NextColor() {
+	c.colorIndex = (c.colorIndex + 1) % len(tcolor.BasicColorList)
+	for tcolor.BasicColorList[c.colorIndex] == tcolor.None || tcolor.BasicColorList[c.colorIndex] == tcolor.Black {
+		c.colorIndex = (c.colorIndex + 1) % len(tcolor.BasicColorList)
+	}
+	color := tcolor.BasicColorList[c.colorIndex]
+	c.colorName = strings.ToLower(color.String())
+	log.LogVf("Switching to color %s", c.colorName)
+	c.color = c.ap.ColorOutput.Foreground(color.Color())
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+
+	"fortio.org/terminal/ansipixels"
+	"fortio.org/terminal/ansipixels/tcolor"
+)
+
+// mouseEvent sets the ap mouse state as decoded from the terminal (1,1 origin).
+func mouseEvent(ap *ansipixels.AnsiPixels, buttons, x, y int, release bool) {
+	ap.Mouse, ap.Mbuttons, ap.Mx, ap.My, ap.Mrelease = true, buttons, x, y, release
+}
+
+func placedClock() *Config {
+	ap := ansipixels.NewAnsiPixels(0)
+	ap.W, ap.H = 80, 24
+	// Clock placed with its bottom right corner at 30,10, 10x3 so covering 20-29 x 7-9 (0,0 origin).
+	return &Config{ap: ap, boxX: 20, boxY: 7, boxW: 10, boxH: 3, radius: 1.2, colorDisc: tcolor.RGBColor{R: 255}}
+}
+
+func TestMouseClickOnPlacedClock(t *testing.T) {
+	c := placedClock()
+	mouseEvent(c.ap, ansipixels.MouseLeft, 25, 9, false)
+	c.Mouse(30, 10)
+	mouseEvent(c.ap, ansipixels.MouseLeft, 25, 9, true)
+	c.Mouse(30, 10)
+	if !c.trackMouse {
+		t.Errorf("click without moving on the placed clock should switch back to following the mouse")
+	}
+	if c.drag != (Drag{}) {
+		t.Errorf("drag not reset: %+v", c.drag)
+	}
+}
+
+func TestMouseDragPlacedClock(t *testing.T) {
+	c := placedClock()
+	mouseEvent(c.ap, ansipixels.MouseLeft, 25, 9, false)
+	x, y, redraw := c.Mouse(30, 10)
+	if redraw {
+		t.Errorf("press shouldn't redraw")
+	}
+	mouseEvent(c.ap, ansipixels.MouseMove|ansipixels.MouseLeft, 35, 12, false)
+	x, y, redraw = c.Mouse(x, y)
+	if !redraw || x != 40 || y != 13 {
+		t.Errorf("drag = %d, %d, %v, want 40, 13, true", x, y, redraw)
+	}
+	mouseEvent(c.ap, ansipixels.MouseLeft, 35, 12, true)
+	c.Mouse(x, y)
+	if c.trackMouse || c.drag.active {
+		t.Errorf("release after a drag should leave the clock placed: track %v, drag %+v", c.trackMouse, c.drag)
+	}
+}
+
+func TestMouseWheel(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(c *Config)
+		wantRadius float64
+	}{
+		{"disc", func(*Config) {}, 1.3},
+		{"no disc", func(c *Config) { c.colorDisc = tcolor.RGBColor{} }, 1.2},
+		{"analog", func(c *Config) { c.analog = true }, 1.2},
+		{"aa", func(c *Config) { c.aa = true }, 1.2},
+	}
+	for _, tt := range tests {
+		c := placedClock()
+		tt.setup(c)
+		mouseEvent(c.ap, ansipixels.MouseWheelUp, 1, 1, false)
+		_, _, redraw := c.Mouse(30, 10)
+		if redraw != (tt.wantRadius != 1.2) || c.radius != tt.wantRadius {
+			t.Errorf("%s: wheel up redraw %v, radius %v, want %v", tt.name, redraw, c.radius, tt.wantRadius)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Plain text, JSON or status lines progress output, for scripts, CI logs and status bars (no terminal handling).
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// OutputMode is the format of the PlainLoop lines.
+type OutputMode int
+
+const (
+	PlainOutput      OutputMode = iota // remaining=00:04:59
+	JSONOutput                         // Progress as JSON.
+	StatusLineOutput                   // StatusLine.
+)
+
+// Progress is one -json output line.
+type Progress struct {
+	Now       time.Time `json:"now"`
+	Target    time.Time `json:"target,omitzero"`
+	Remaining string    `json:"remaining,omitempty"`
+	State     string    `json:"state"` // clock, running, paused, done or aborted.
+}
+
+// HHMMSS formats a duration as 00:04:59 (hours can go past 24).
+func HHMMSS(d time.Duration) string {
+	s := int(d.Round(time.Second).Seconds())
+	return fmt.Sprintf("%02d:%02d:%02d", s/3600, (s/60)%60, s%60)
+}
+
+// Progress is the current state, state overrides the running/paused one when not empty.
+func (c *Config) Progress(now time.Time, state string) Progress {
+	p := Progress{Now: now, State: "clock"}
+	if !c.countDown {
+		if state != "" {
+			p.State = state
+		}
+		return p
+	}
+	p.Target = c.end
+	p.Remaining = HHMMSS(max(0, c.end.Sub(c.Timer(now))))
+	p.State = "running"
+	if !c.pausedAt.IsZero() {
+		p.State = "paused"
+	}
+	if state != "" {
+		p.State = state
+	}
+	return p
+}
+
+// String is the -plain output: the (24h) time or the countdown remaining time and state.
+func (p Progress) String() string {
+	if p.Remaining == "" {
+		return "now=" + p.Now.Format("15:04:05")
+	}
+	if p.State == "running" {
+		return "remaining=" + p.Remaining
+	}
+	return "remaining=" + p.Remaining + " state=" + p.State
+}
+
+// PlainLoop prints the time, or the countdown remaining time, once per second as text, JSON or status lines
+// until the countdown expires (exit code 0) or a signal or the quit command aborts it (exit code 1, 0 for
+// the clock). Control commands (-ctl, -http, SIGUSR1/2) are still handled. When following the countdown of
+// another tclock (followState), its state is reloaded every second and there is no expiry.
+func (c *Config) PlainLoop(mode OutputMode) int {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	emit := func(p Progress) {
+		switch mode {
+		case JSONOutput:
+			data, _ := json.Marshal(p)
+			fmt.Println(string(data))
+		case StatusLineOutput:
+			fmt.Println(c.StatusLine(p.Now))
+		default:
+			fmt.Println(p)
+		}
+	}
+	// Interrupted by a signal or the quit command.
+	abort := func() int {
+		if mode != StatusLineOutput { // the status bar just keeps the last line.
+			emit(c.Progress(c.clock.Now(), "aborted"))
+		}
+		if c.countDown && !c.followState {
+			return 1
+		}
+		return 0
+	}
+	for {
+		now := c.clock.Now()
+		if c.followState {
+			c.FollowState()
+		}
+		if c.countDown && c.pausedAt.IsZero() && !c.end.After(now) && !c.followState {
+			emit(c.Progress(now, "done"))
+			return 0
+		}
+		emit(c.Progress(now, ""))
+		// Next tick on the next second of the countdown (or of the clock).
+		next := c.end.Sub(now) % time.Second
+		if next <= 0 {
+			next = time.Second
+		}
+		if !c.countDown || !c.pausedAt.IsZero() {
+			next = now.Truncate(time.Second).Add(time.Second).Sub(now)
+		}
+		timer := time.NewTimer(c.clock.Real(next))
+	wait:
+		select {
+		case <-timer.C:
+		case <-sigs:
+			timer.Stop()
+			return abort()
+		case cmd := <-c.commands:
+			reply, quit := c.Execute(cmd)
+			cmd.Reply <- reply
+			if quit {
+				timer.Stop()
+				return abort()
+			}
+			goto wait
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHHMMSS(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "00:00:00"},
+		{299 * time.Second, "00:04:59"},
+		{299*time.Second + 600*time.Millisecond, "00:05:00"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "01:02:03"},
+		{30 * time.Hour, "30:00:00"},
+	}
+	for _, tt := range tests {
+		if got := HHMMSS(tt.d); got != tt.want {
+			t.Errorf("HHMMSS(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestProgress(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	end := now.Add(5 * time.Minute)
+	tests := []struct {
+		name  string
+		c     *Config
+		state string
+		want  Progress
+	}{
+		{"clock", &Config{}, "", Progress{Now: now, State: "clock"}},
+		{"clock aborted", &Config{}, "aborted", Progress{Now: now, State: "aborted"}},
+		{"running", &Config{countDown: true, end: end}, "", Progress{now, end, "00:05:00", "running"}},
+		{
+			"paused", &Config{countDown: true, end: end, pausedAt: now.Add(-time.Minute)}, "",
+			Progress{now, end, "00:06:00", "paused"},
+		},
+		{
+			"expired", &Config{countDown: true, end: now.Add(-time.Second)}, "done",
+			Progress{now, now.Add(-time.Second), "00:00:00", "done"},
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.c.Progress(now, tt.state); got != tt.want {
+			t.Errorf("%s: Progress() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProgressString(t *testing.T) {
+	now := time.Date(2026, 1, 1, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		p    Progress
+		want string
+	}{
+		{Progress{Now: now, State: "clock"}, "now=15:04:05"},
+		{Progress{Now: now, State: "aborted"}, "now=15:04:05"},
+		{Progress{Now: now, Remaining: "00:04:59", State: "running"}, "remaining=00:04:59"},
+		{Progress{Now: now, Remaining: "00:04:59", State: "paused"}, "remaining=00:04:59 state=paused"},
+		{Progress{Now: now, Remaining: "00:04:59", State: "aborted"}, "remaining=00:04:59 state=aborted"},
+	}
+	for _, tt := range tests {
+		if got := tt.p.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestPlainLoopQuit(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		c    *Config
+		want int
+	}{
+		{"clock", &Config{}, 0},
+		{"countdown", &Config{countDown: true, end: now.Add(time.Hour)}, 1},
+	}
+	for _, tt := range tests {
+		tt.c.clock = NewFakeClock(now, 1)
+		tt.c.commands = make(chan Command)
+		go func() {
+			cmd := ParseCommand("quit")
+			tt.c.commands <- cmd
+			<-cmd.Reply
+		}()
+		if got := tt.c.PlainLoop(StatusLineOutput); got != tt.want {
+			t.Errorf("%s: PlainLoop() after quit = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
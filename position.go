@@ -0,0 +1,94 @@
+// Pinned clock positions (corners, top center) for tail and normal modes.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Position int
+
+const (
+	// MousePosition is the default normal mode: centered then following the mouse until clicked.
+	MousePosition Position = iota
+	TopLeft
+	TopRight
+	BottomLeft
+	BottomRight
+	TopCenter
+)
+
+var positionNames = []string{"mouse", "top-left", "top-right", "bottom-left", "bottom-right", "top-center"}
+
+func (p Position) String() string {
+	return positionNames[p]
+}
+
+// ParsePosition parses one of the positionNames ("" is the MousePosition default).
+func ParsePosition(s string) (Position, error) {
+	if s == "" {
+		return MousePosition, nil
+	}
+	for i, name := range positionNames {
+		if s == name {
+			return Position(i), nil
+		}
+	}
+	return MousePosition, fmt.Errorf("invalid position %q, should be one of %s", s, strings.Join(positionNames[1:], ", "))
+}
+
+func (p Position) Bottom() bool {
+	return p == BottomLeft || p == BottomRight
+}
+
+// linesBelow is the number of lines drawn under the (boxed) clock: the text and, in tail mode, the rate sparkline.
+func (c *Config) linesBelow() int {
+	n := 0
+	if c.text != "" {
+		n++
+	}
+	if c.tailMode {
+		n++
+	}
+	return n
+}
+
+// Pinned returns the bottom right corner (0,0 origin) for a width x height clock at the configured
+// position and margin.
+func (c *Config) Pinned(width, height int) (x, y int) {
+	m := c.margin
+	switch c.position {
+	case TopLeft, BottomLeft:
+		x = width - 1 + m
+	case TopRight, BottomRight:
+		x = c.ap.W - 1 - m
+	default: // TopCenter (and MousePosition, unused)
+		x = c.ap.W/2 + width/2
+	}
+	y = height - 1 + m
+	if c.position.Bottom() {
+		y = c.ap.H - 1 - m - c.linesBelow()
+	}
+	return x, y
+}
+
+// BandTop is the first line of the lines reserved for the clock in tail mode.
+func (c *Config) BandTop() int {
+	if c.position.Bottom() {
+		return c.ap.H - c.ReservedLines()
+	}
+	return 0
+}
+
+// TailTop is the first line of the tail area and TailRows its number of lines.
+func (c *Config) TailTop() int {
+	if c.position.Bottom() {
+		return 0
+	}
+	return c.ReservedLines()
+}
+
+func (c *Config) TailRows() int {
+	return c.ap.H - c.ReservedLines()
+}
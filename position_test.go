@@ -0,0 +1,97 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"fortio.org/terminal/ansipixels"
+)
+
+func TestParsePosition(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Position
+		wantErr bool
+	}{
+		{"", MousePosition, false},
+		{"mouse", MousePosition, false},
+		{"top-left", TopLeft, false},
+		{"top-right", TopRight, false},
+		{"bottom-left", BottomLeft, false},
+		{"bottom-right", BottomRight, false},
+		{"top-center", TopCenter, false},
+		{"Top-Left", MousePosition, true},
+		{"center", MousePosition, true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePosition(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParsePosition(%q) = %v, %v, want %v (error %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+		if err == nil && tt.in != "" && got.String() != tt.in {
+			t.Errorf("%v.String() = %q, want %q", got, got.String(), tt.in)
+		}
+	}
+}
+
+func TestPinned(t *testing.T) {
+	tests := []struct {
+		position Position
+		margin   int
+		text     string
+		x, y     int
+	}{
+		{TopLeft, 0, "", 9, 2},
+		{TopLeft, 1, "", 10, 3},
+		{TopRight, 0, "", 79, 2},
+		{TopRight, 2, "", 77, 4},
+		{BottomLeft, 0, "", 9, 23},
+		{BottomRight, 1, "text", 78, 21},
+		{TopCenter, 0, "", 45, 2},
+	}
+	for _, tt := range tests {
+		ap := ansipixels.NewAnsiPixels(0)
+		ap.W, ap.H = 80, 24
+		c := &Config{ap: ap, position: tt.position, margin: tt.margin, text: tt.text}
+		if x, y := c.Pinned(10, 3); x != tt.x || y != tt.y {
+			t.Errorf("%v margin %d text %q: Pinned(10, 3) = %d, %d, want %d, %d",
+				tt.position, tt.margin, tt.text, x, y, tt.x, tt.y)
+		}
+	}
+}
+
+func TestPinnedDisc(t *testing.T) {
+	for _, position := range []Position{TopLeft, TopRight, BottomLeft, BottomRight, TopCenter} {
+		c := testConfig()
+		c.position, c.margin = position, 1
+		var sb strings.Builder
+		if err := c.Snapshot(&sb, snapshotTime, 80, 20, true); err != nil {
+			t.Fatalf("Snapshot: %v", err)
+		}
+		lines := strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+		if len(lines) != 20 || lines[0] != "" || lines[19] != "" {
+			t.Errorf("%v: want 20 lines with empty first and last ones (margin), got:\n%s", position, sb.String())
+			continue
+		}
+		first, last, widest := -1, -1, 0
+		for i, line := range lines {
+			if line != "" && !strings.HasPrefix(line, " ") || utf8.RuneCountInString(line) >= 80 {
+				t.Errorf("%v: line %d %q touches the left or right edge", position, i, line)
+			}
+			if n := utf8.RuneCountInString(strings.TrimSpace(line)); n > 0 {
+				if first < 0 {
+					first = i
+				}
+				last = i
+				widest = max(widest, n)
+			}
+		}
+		// The round top and bottom of the disc are visible, not cut by the screen edges.
+		for _, i := range []int{first, last} {
+			if 2*utf8.RuneCountInString(strings.TrimSpace(lines[i])) > widest {
+				t.Errorf("%v: disc cut at line %d %q, got:\n%s", position, i, lines[i], sb.String())
+			}
+		}
+	}
+}
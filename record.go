@@ -0,0 +1,99 @@
+// Recording of the terminal output as an asciinema v2 (.cast) file (-record).
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+	"unicode/utf8"
+)
+
+// castHeader is the first line of an asciicast v2 file.
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Recorder writes what is written to it as timed asciicast output events.
+type Recorder struct {
+	file    *os.File
+	out     *bufio.Writer
+	start   time.Time
+	w, h    int
+	pending []byte // incomplete utf-8 sequence at the end of the previous write.
+}
+
+// NewRecorder creates the .cast file for a w x h terminal and writes its header.
+func NewRecorder(path string, w, h int) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Recorder{file: file, out: bufio.NewWriter(file), start: time.Now(), w: w, h: h}
+	header := castHeader{Version: 2, Width: w, Height: h, Timestamp: r.start.Unix(), Env: map[string]string{}}
+	for _, name := range []string{"TERM", "SHELL"} {
+		if v := os.Getenv(name); v != "" {
+			header.Env[name] = v
+		}
+	}
+	if err = r.line(header); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Recorder) line(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	_, err = r.out.Write(data)
+	return err
+}
+
+func (r *Recorder) event(code, data string) error {
+	return r.line([]any{time.Since(r.start).Seconds(), code, data})
+}
+
+// Write records data as an output event (keeping utf-8 sequences split across writes whole).
+func (r *Recorder) Write(data []byte) (int, error) {
+	buf := append(r.pending, data...)
+	end := len(buf)
+	for i := max(0, end-utf8.UTFMax+1); i < end; i++ {
+		if utf8.RuneStart(buf[i]) && !utf8.FullRune(buf[i:]) {
+			end = i
+			break
+		}
+	}
+	r.pending = append([]byte(nil), buf[end:]...)
+	if end == 0 {
+		return len(data), nil
+	}
+	return len(data), r.event("o", string(buf[:end]))
+}
+
+// Resize records the terminal size if it changed.
+func (r *Recorder) Resize(w, h int) {
+	if w == r.w && h == r.h {
+		return
+	}
+	r.w, r.h = w, h
+	_ = r.event("r", fmt.Sprintf("%dx%d", w, h))
+}
+
+// Close flushes and closes the .cast file.
+func (r *Recorder) Close() error {
+	err := r.out.Flush()
+	if cerr := r.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// castEvents returns the header and the code and data of the events of a .cast file.
+func castEvents(t *testing.T, path string) (castHeader, [][2]string) {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	var header castHeader
+	var events [][2]string
+	for i := 0; scanner.Scan(); i++ {
+		if i == 0 {
+			if err = json.Unmarshal(scanner.Bytes(), &header); err != nil {
+				t.Fatalf("header: %v", err)
+			}
+			continue
+		}
+		var event []any
+		if err = json.Unmarshal(scanner.Bytes(), &event); err != nil || len(event) != 3 {
+			t.Fatalf("event %q: %v", scanner.Text(), err)
+		}
+		events = append(events, [2]string{event[1].(string), event[2].(string)})
+	}
+	return header, events
+}
+
+func TestRecorder(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   [][2]string
+	}{
+		{"ascii", []string{"ab", "c"}, [][2]string{{"o", "ab"}, {"o", "c"}}},
+		{"whole runes", []string{"⏱ ▄"}, [][2]string{{"o", "⏱ ▄"}}},
+		// ▄ is e2 96 84: kept whole in the next event.
+		{"split rune", []string{"a\xe2\x96", "\x84b"}, [][2]string{{"o", "a"}, {"o", "▄b"}}},
+		{"split thrice", []string{"\xe2", "\x96", "\x84"}, [][2]string{{"o", "▄"}}},
+		{"split 4 bytes", []string{"x\xf0\x9f", "\x98\x80"}, [][2]string{{"o", "x"}, {"o", "😀"}}},
+		{"invalid bytes", []string{"a\xffb"}, [][2]string{{"o", "a�b"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "test.cast")
+			r, err := NewRecorder(path, 80, 24)
+			if err != nil {
+				t.Fatalf("NewRecorder: %v", err)
+			}
+			for _, w := range tt.writes {
+				if n, err := r.Write([]byte(w)); n != len(w) || err != nil {
+					t.Errorf("Write(%q) = %d, %v", w, n, err)
+				}
+			}
+			r.Resize(80, 24) // unchanged: no event.
+			if err = r.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+			header, events := castEvents(t, path)
+			if header.Version != 2 || header.Width != 80 || header.Height != 24 {
+				t.Errorf("header %+v", header)
+			}
+			if len(events) != len(tt.want) {
+				t.Fatalf("events %q, want %q", events, tt.want)
+			}
+			for i := range events {
+				if events[i] != tt.want[i] {
+					t.Errorf("events %q, want %q", events, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestRecorderResize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.cast")
+	r, err := NewRecorder(path, 80, 24)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	r.Resize(100, 30)
+	r.Resize(100, 30)
+	if err = r.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, events := castEvents(t, path); len(events) != 1 || events[0] != [2]string{"r", "100x30"} {
+		t.Errorf("events %q, want one 100x30 resize", events)
+	}
+}
@@ -0,0 +1,137 @@
+// Live reload of the config file, on SIGHUP or when the file changes.
+
+package main
+
+import (
+	"flag"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"fortio.org/log"
+)
+
+// reloadCheckInterval is how often the config file modification time is checked.
+const reloadCheckInterval = time.Second
+
+// Reloader re-reads the config file (and preset) and re-applies the flags it sets,
+// the ones explicitly set on the command line or environment still take precedence.
+type Reloader struct {
+	Path     string
+	Preset   string
+	Explicit map[string]bool
+	// Apply applies the (look) flags to the current Config, changed are the flags whose value
+	// changed with the reload (the others shouldn't override runtime changes).
+	Apply     func(changed map[string]bool) error
+	modTime   time.Time
+	nextCheck time.Time
+	hup       chan os.Signal
+}
+
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// Start records the current modification time and starts catching SIGHUP.
+func (r *Reloader) Start() {
+	r.modTime = fileModTime(r.Path)
+	r.hup = make(chan os.Signal, 1)
+	signal.Notify(r.hup, syscall.SIGHUP)
+}
+
+// Check returns true when a reload is needed: SIGHUP received or the file changed.
+func (r *Reloader) Check(now time.Time) bool {
+	select {
+	case <-r.hup:
+		log.Infof("SIGHUP received, reloading %s", r.Path)
+		r.modTime = fileModTime(r.Path)
+		return true
+	default:
+	}
+	if now.Before(r.nextCheck) {
+		return false
+	}
+	r.nextCheck = now.Add(reloadCheckInterval)
+	modTime := fileModTime(r.Path)
+	if modTime.Equal(r.modTime) {
+		return false
+	}
+	log.Infof("%s changed, reloading", r.Path)
+	r.modTime = modTime
+	return true
+}
+
+// Reload resets the flags not explicitly set to their defaults, applies the config file
+// and then the flags that changed to the Config. On error (e.g. invalid file) the current settings are kept.
+func (r *Reloader) Reload() error {
+	cf, err := LoadConfigFile(r.Path, false)
+	if err != nil {
+		return err
+	}
+	saved := make(map[string]string)
+	flag.VisitAll(func(f *flag.Flag) {
+		if r.Explicit[f.Name] || slices.Contains(cliFlags, f.Name) || slices.Contains(metaFlags, f.Name) {
+			return
+		}
+		saved[f.Name] = f.Value.String()
+		_ = f.Value.Set(f.DefValue)
+	})
+	if err = cf.Apply(r.Preset, r.Explicit); err == nil {
+		changed := make(map[string]bool)
+		for name, value := range saved {
+			if flag.Lookup(name).Value.String() != value {
+				changed[name] = true
+			}
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+		log.LogVf("Reloaded flags changed: %v", slices.Sorted(maps.Keys(changed)))
+		err = r.Apply(changed)
+	}
+	if err != nil {
+		for name, value := range saved {
+			_ = flag.Set(name, value)
+		}
+		_ = r.Apply(nil)
+	}
+	return err
+}
+
+// RuntimeLook is the part of the look that can also be changed while running: color (ctl color
+// or right click), disc radius (mouse wheel) and text (ctl text).
+type RuntimeLook struct {
+	color, colorName string
+	colorIndex       int
+	breath           bool
+	radius           float64
+	text             string
+	autoText         bool
+}
+
+func (c *Config) RuntimeLook() RuntimeLook {
+	return RuntimeLook{
+		color: c.color, colorName: c.colorName, colorIndex: c.colorIndex, breath: c.breath,
+		radius: c.radius, text: c.text, autoText: c.autoText,
+	}
+}
+
+// KeepRuntimeLook restores the runtime look l, except for the parts whose flags changed.
+func (c *Config) KeepRuntimeLook(l RuntimeLook, changed map[string]bool) {
+	if !changed["color"] && !changed["breath"] {
+		c.color, c.colorName, c.colorIndex, c.breath = l.color, l.colorName, l.colorIndex, l.breath
+	}
+	if !changed["radius"] {
+		c.radius = l.radius
+	}
+	if !changed["text"] {
+		c.text, c.autoText = l.text, l.autoText
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestKeepRuntimeLook(t *testing.T) {
+	tests := []struct {
+		name       string
+		changed    map[string]bool
+		wantColor  string
+		wantRadius float64
+		wantText   string
+	}{
+		{"nothing changed", nil, "blue", 2, "Break"},
+		{"other flags changed", map[string]bool{"box": true}, "blue", 2, "Break"},
+		{"color changed", map[string]bool{"color": true}, "green", 2, "Break"},
+		{"breath changed", map[string]bool{"breath": true}, "green", 2, "Break"},
+		{"radius and text changed", map[string]bool{"radius": true, "text": true}, "blue", 1.5, "Lunch"},
+	}
+	for _, tt := range tests {
+		// Runtime changes (ctl color, wheel, ctl text)...
+		c := &Config{colorName: "blue", radius: 2, text: "Break"}
+		look := c.RuntimeLook()
+		// ...then the reloaded config values.
+		c.colorName, c.radius, c.text = "green", 1.5, "Lunch"
+		c.KeepRuntimeLook(look, tt.changed)
+		if c.colorName != tt.wantColor || c.radius != tt.wantRadius || c.text != tt.wantText {
+			t.Errorf("%s: got %s, %v, %s, want %s, %v, %s", tt.name, c.colorName, c.radius, c.text,
+				tt.wantColor, tt.wantRadius, tt.wantText)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Export of the clock as image files (tclock render): analog PNG and animated GIF, digital SVG.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fortio.org/terminal/ansipixels/tcolor"
+)
+
+// svgColors are the RGB values used in SVG for the basic (terminal palette dependent) colors.
+var svgColors = map[tcolor.BasicColor]string{
+	tcolor.Black: "#000000", tcolor.Red: "#e02020", tcolor.Green: "#20c020", tcolor.Yellow: "#e0e020",
+	tcolor.Orange: "#ff9020", tcolor.Blue: "#4060ff", tcolor.Purple: "#c040c0", tcolor.Cyan: "#20c0c0",
+	tcolor.Gray: "#aaaaaa", tcolor.DarkGray: "#555555", tcolor.BrightRed: "#ff5050", tcolor.BrightGreen: "#50ff50",
+	tcolor.BrightYellow: "#ffff50", tcolor.BrightBlue: "#7080ff", tcolor.BrightPurple: "#ff50ff",
+	tcolor.BrightCyan: "#50ffff", tcolor.White: "#ffffff",
+}
+
+// svgColor converts a -color value to an SVG color.
+func svgColor(name string) string {
+	c, err := tcolor.FromString(name)
+	if err != nil {
+		return svgColors[tcolor.Red]
+	}
+	if basic, ok := c.BasicColor(); ok {
+		if s, found := svgColors[basic]; found {
+			return s
+		}
+	}
+	rgb := RGBColor(c)
+	return fmt.Sprintf("#%02x%02x%02x", rgb.R, rgb.G, rgb.B)
+}
+
+// RenderImage is the analog clock at now in a w x h pixels image, transparent unless -black-bg.
+func (c *Config) RenderImage(w, h int, now time.Time) *image.NRGBA {
+	img := c.ClockImage(w, h, now, c.seconds, max(1, float64(min(w, h))/100))
+	if !c.fillBlack {
+		return img
+	}
+	res := image.NewNRGBA(img.Bounds())
+	draw.Draw(res, res.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	draw.Draw(res, res.Bounds(), img, image.Point{}, draw.Over)
+	return res
+}
+
+// WriteGIF writes an animated GIF of the analog clock, one frame per second starting at now.
+func (c *Config) WriteGIF(out io.Writer, w, h int, now time.Time, frames int) error {
+	anim := &gif.GIF{}
+	for i := range max(1, frames) {
+		img := c.RenderImage(w, h, now.Add(time.Duration(i)*time.Second))
+		// GIF has no partial transparency: draw on black.
+		frame := image.NewPaletted(img.Bounds(), palette.Plan9)
+		bg := image.NewRGBA(img.Bounds())
+		draw.Draw(bg, bg.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+		draw.Draw(bg, bg.Bounds(), img, image.Point{}, draw.Over)
+		draw.FloydSteinberg.Draw(frame, frame.Bounds(), bg, image.Point{})
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, 100) // 1s, in 100ths of a second.
+	}
+	return gif.EncodeAll(out, anim)
+}
+
+// Segment sizes of the SVG digits: the terminal characters cells are svgCellW x svgCellH.
+const (
+	svgCellW = 10
+	svgCellH = 20
+	svgThick = 4
+)
+
+// WriteSVG writes the digital clock (same segments as the terminal display) at now as SVG, w pixels wide.
+func (c *Config) WriteSVG(out io.Writer, w int, now time.Time) error {
+	lines := strings.Split(strings.TrimRight(TimeString(c.FrameString(now), false), "\n"), "\n")
+	cols := 0
+	for _, line := range lines {
+		cols = max(cols, len([]rune(line)))
+	}
+	vw, vh := cols*svgCellW, len(lines)*svgCellH
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n",
+		w, w*vh/max(1, vw), vw, vh)
+	if c.fillBlack {
+		fmt.Fprintf(&sb, "<rect width=\"%d\" height=\"%d\" fill=\"#000000\"/>\n", vw, vh)
+	}
+	fmt.Fprintf(&sb, "<g fill=\"%s\">\n", svgColor(c.colorName))
+	for y, line := range lines {
+		for x, r := range []rune(line) {
+			px, py := x*svgCellW, y*svgCellH
+			switch r {
+			case '━':
+				fmt.Fprintf(&sb, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\"/>\n",
+					px, py+(svgCellH-svgThick)/2, svgCellW, svgThick)
+			case '┃':
+				fmt.Fprintf(&sb, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\"/>\n",
+					px+(svgCellW-svgThick)/2, py, svgThick, svgCellH)
+			case ':', '.':
+				fmt.Fprintf(&sb, "<circle cx=\"%d\" cy=\"%d\" r=\"%d\"/>\n", px+svgCellW/2, py+svgCellH/2, svgThick/2+1)
+			}
+		}
+	}
+	sb.WriteString("</g>\n</svg>\n")
+	_, err := io.WriteString(out, sb.String())
+	return err
+}
+
+// Render writes the clock at now to path, the format depends on the extension: .png (analog),
+// .gif (analog, animated when frames > 1) or .svg (digital).
+func (c *Config) Render(path string, w, h int, now time.Time, frames int) error {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".png", ".gif", ".svg":
+	default:
+		return fmt.Errorf("unsupported output %q, should end with .png, .gif or .svg", path)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	out := bufio.NewWriter(file)
+	switch ext {
+	case ".png":
+		err = png.Encode(out, c.RenderImage(w, h, now))
+	case ".gif":
+		err = c.WriteGIF(out, w, h, now, frames)
+	default:
+		err = c.WriteSVG(out, w, now)
+	}
+	if err == nil {
+		err = out.Flush()
+	}
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
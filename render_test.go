@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"image/gif"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSVGColor(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"red", "#e02020"},
+		{"BrightCyan", "#50ffff"},
+		{"1020FF", "#1020ff"},
+		{"not a color", "#e02020"},
+	}
+	for _, tt := range tests {
+		if got := svgColor(tt.name); got != tt.want {
+			t.Errorf("svgColor(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	c := testConfig()
+	dir := t.TempDir()
+	if err := c.Render(filepath.Join(dir, "clock.jpg"), 64, 64, snapshotTime, 1); err == nil {
+		t.Errorf("expected an error for .jpg")
+	}
+	var svg strings.Builder
+	if err := c.WriteSVG(&svg, 300, snapshotTime); err != nil {
+		t.Fatalf("WriteSVG: %v", err)
+	}
+	if s := svg.String(); !strings.HasPrefix(s, "<svg ") || !strings.Contains(s, `<g fill="#e02020">`) ||
+		strings.Count(s, "<circle") != 4 { // the 2 colons.
+		t.Errorf("unexpected svg:\n%s", s)
+	}
+	var anim bytes.Buffer
+	if err := c.WriteGIF(&anim, 64, 64, snapshotTime, 3); err != nil {
+		t.Fatalf("WriteGIF: %v", err)
+	}
+	g, err := gif.DecodeAll(&anim)
+	if err != nil {
+		t.Fatalf("DecodeAll: %v", err)
+	}
+	if len(g.Image) != 3 || g.Config.Width != 64 {
+		t.Errorf("gif: %d frames, %d wide, want 3, 64", len(g.Image), g.Config.Width)
+	}
+}
@@ -0,0 +1,94 @@
+// Running a child command with its output tailed under the clock.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"fortio.org/log"
+	"fortio.org/terminal"
+	"fortio.org/terminal/ansipixels/tcolor"
+)
+
+// CommandArgs returns the arguments after a "--" on the command line, if any.
+// (flag parsing removes the "--" so we check the original os.Args).
+func CommandArgs(args []string) []string {
+	idx := slices.Index(os.Args[1:], "--")
+	if idx < 0 || len(args) != len(os.Args)-idx-2 {
+		return nil
+	}
+	return args
+}
+
+// ShellQuote joins args as a shell command line, single quoting the ones that need it
+// (e.g. sh -c 'a; b').
+func ShellQuote(args []string) string {
+	unsafe := func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && !strings.ContainsRune("-_./=:,+@%", r)
+	}
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && strings.IndexFunc(arg, unsafe) < 0 {
+			quoted[i] = arg
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+// StartCommand starts the given command with both its stdout and stderr going
+// to the tail reader. The clock then counts the elapsed time.
+func (c *Config) StartCommand(args []string) error {
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	c.cmd = exec.Command(args[0], args[1:]...) //nolint:gosec // running the user's command is the point.
+	c.cmd.Stdout = pw
+	c.cmd.Stderr = pw
+	setProcessGroup(c.cmd)
+	err = c.cmd.Start()
+	_ = pw.Close() // the child has its own copy, we get EOF when it's done.
+	if err != nil {
+		_ = pr.Close()
+		return err
+	}
+	log.LogVf("Started %q pid %d", args, c.cmd.Process.Pid)
+	c.tail = terminal.NewTimeoutReader(pr, time.Millisecond)
+	c.countUp = true
+	c.start = c.now
+	return nil
+}
+
+// CommandDone waits for the command to finish and reports its exit status
+// and duration. Returns the exit code to use for tclock itself.
+func (c *Config) CommandDone(writer *terminal.CRLFWriter) int {
+	err := c.cmd.Wait()
+	elapsed := c.clock.Now().Sub(c.start).Round(time.Millisecond)
+	code := c.cmd.ProcessState.ExitCode()
+	if err != nil && code <= 0 {
+		code = 1 // killed by a signal for instance.
+	}
+	c.ap.RestoreCursorPos()
+	color := tcolor.Green
+	if code != 0 {
+		color = tcolor.Red
+	}
+	_, _ = fmt.Fprintf(writer, "\n%sCommand exited with status %d after %v%s\n", color.Foreground(), code, elapsed, tcolor.Reset)
+	return code
+}
+
+// KillCommand stops the command if it's still running (eg. on 'q').
+func (c *Config) KillCommand() {
+	if c.cmd == nil || c.cmd.ProcessState != nil {
+		return
+	}
+	_ = killProcessGroup(c.cmd)
+	_ = c.cmd.Wait()
+}
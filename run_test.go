@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestCommandArgs(t *testing.T) {
+	saved := os.Args
+	defer func() { os.Args = saved }()
+	tests := []struct {
+		osArgs []string
+		args   []string // flag.Args() after parsing.
+		want   []string
+	}{
+		{[]string{"tclock", "-box", "--", "ls", "-l"}, []string{"ls", "-l"}, []string{"ls", "-l"}},
+		{[]string{"tclock", "--", "sh", "-c", "a -- b"}, []string{"sh", "-c", "a -- b"}, []string{"sh", "-c", "a -- b"}},
+		{[]string{"tclock", "5:00"}, []string{"5:00"}, nil},
+		{[]string{"tclock"}, nil, nil},
+		// Not the "--" ending the flags: a regular argument.
+		{[]string{"tclock", "x", "--", "ls"}, []string{"x", "--", "ls"}, nil},
+	}
+	for _, tt := range tests {
+		os.Args = tt.osArgs
+		if got := CommandArgs(tt.args); !slices.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+			t.Errorf("CommandArgs(%q) with os.Args %q = %q, want %q", tt.args, tt.osArgs, got, tt.want)
+		}
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"ls", "-l", "/tmp/a_b.txt"}, "ls -l /tmp/a_b.txt"},
+		{[]string{"sh", "-c", "a; b"}, "sh -c 'a; b'"},
+		{[]string{"echo", "it's", ""}, `echo 'it'\''s' ''`},
+		{[]string{"grep", "$HOME", "*.go"}, "grep '$HOME' '*.go'"},
+	}
+	for _, tt := range tests {
+		if got := ShellQuote(tt.args); got != tt.want {
+			t.Errorf("ShellQuote(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+// Scrollback buffer of the tailed output, with pause, scrolling and search.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"fortio.org/terminal/ansipixels"
+	"fortio.org/terminal/ansipixels/tcolor"
+)
+
+// Scrollback is a ring buffer of the last Max lines written (without ANSI codes).
+type Scrollback struct {
+	Max     int
+	lines   []string
+	first   int    // index of the oldest line once the ring is full.
+	partial []byte // current, not yet newline terminated, line.
+	Total   int    // number of lines ever added.
+	Dropped int    // number of (oldest) lines evicted from the ring, At indexes shift by as much.
+}
+
+func (s *Scrollback) add(line string) {
+	s.Total++
+	if len(s.lines) < s.Max {
+		s.lines = append(s.lines, line)
+		return
+	}
+	s.Dropped++
+	s.lines[s.first] = line
+	s.first = (s.first + 1) % s.Max
+}
+
+// Write appends the data, splitting it into lines. Always succeeds.
+func (s *Scrollback) Write(p []byte) (int, error) {
+	if s.Max <= 0 {
+		return len(p), nil
+	}
+	n := len(p)
+	for {
+		idx := bytes.IndexByte(p, '\n')
+		if idx < 0 {
+			s.partial = append(s.partial, p...)
+			return n, nil
+		}
+		s.partial = append(s.partial, p[:idx]...)
+		clean, _ := ansipixels.AnsiClean(s.partial)
+		s.add(strings.TrimRight(string(clean), "\r"))
+		s.partial = s.partial[:0]
+		p = p[idx+1:]
+	}
+}
+
+// Len is the number of lines, including the current partial one if any.
+func (s *Scrollback) Len() int {
+	if len(s.partial) > 0 {
+		return len(s.lines) + 1
+	}
+	return len(s.lines)
+}
+
+// At returns the i-th line, 0 being the oldest.
+func (s *Scrollback) At(i int) string {
+	if i == len(s.lines) {
+		clean, _ := ansipixels.AnsiClean(s.partial)
+		return string(clean)
+	}
+	return s.lines[(s.first+i)%len(s.lines)]
+}
+
+// Find returns the index of the closest line containing str, starting at from
+// and going backward (towards older lines) or forward. -1 if not found.
+func (s *Scrollback) Find(str string, from int, backward bool) int {
+	step := 1
+	if backward {
+		step = -1
+	}
+	for i := from; i >= 0 && i < s.Len(); i += step {
+		if strings.Contains(s.At(i), str) {
+			return i
+		}
+	}
+	return -1
+}
+
+// TailView is the paused/scrolled/searching state of the tail area.
+type TailView struct {
+	paused   bool
+	offset   int // how many lines up from the last one the view is scrolled.
+	newLines int // lines received since paused.
+	inSearch bool
+	input    []byte
+	search   string
+	match    int // index of the current search match.
+	// Scrollback Total, Dropped and Len when last drawn, to count the new lines and keep the view
+	// and match on the same lines.
+	lastTotal, lastDropped, lastLen int
+}
+
+// viewRows is the number of lines available to show the scrollback, keeping the last one for the status.
+func (c *Config) viewRows() int {
+	return max(1, c.TailRows()-1)
+}
+
+func (c *Config) scrollBy(delta int) {
+	v := &c.view
+	v.paused = true
+	v.offset = max(0, min(v.offset+delta, c.tailOut.Lines.Len()-c.viewRows()))
+}
+
+// showMatch scrolls so the current match is in the middle of the view.
+func (c *Config) showMatch() {
+	v := &c.view
+	v.paused = true
+	v.offset = 0
+	c.scrollBy(c.tailOut.Lines.Len() - 1 - v.match - c.viewRows()/2)
+}
+
+// Follow resumes following the tail: the last lines are redrawn from the scrollback
+// and the new output is streamed again.
+func (c *Config) Follow() {
+	c.view = TailView{search: c.view.search}
+	c.tailOut.Hold = false
+	c.SetupTailArea()
+	lines := c.tailOut.Lines
+	rows := c.TailRows()
+	if len(lines.partial) == 0 {
+		rows-- // leave the last row for the next line.
+	}
+	for i := max(0, lines.Len()-rows); i < lines.Len(); i++ {
+		line := truncate(lines.At(i), c.ap.W)
+		if i < lines.Len()-1 || len(lines.partial) == 0 {
+			line += "\r\n"
+		}
+		c.ap.WriteString(line)
+	}
+	c.ap.SaveCursorPos()
+}
+
+// TailKey handles the scrollback keys (and the search input). Returns true if the key was used.
+func (c *Config) TailKey(data []byte) bool {
+	v := &c.view
+	if v.inSearch {
+		switch b := data[0]; b {
+		case '\r', '\n':
+			v.inSearch = false
+			v.search = string(v.input)
+			v.match = c.tailOut.Lines.Len() - v.offset
+			c.findNext(true)
+		case 27, 3: // Esc or Ctrl-C cancels the search input.
+			v.inSearch = false
+		case 127, 8:
+			if len(v.input) > 0 {
+				v.input = v.input[:len(v.input)-1]
+			}
+		default:
+			if b >= ' ' {
+				v.input = append(v.input, data...)
+			}
+		}
+		return true
+	}
+	rows := c.viewRows()
+	switch {
+	case bytes.HasPrefix(data, []byte("\x1b[5~")): // PgUp
+		c.scrollBy(rows)
+	case bytes.HasPrefix(data, []byte("\x1b[6~")): // PgDn
+		c.scrollBy(-rows)
+	case bytes.HasPrefix(data, []byte("\x1b[A")):
+		c.scrollBy(1)
+	case bytes.HasPrefix(data, []byte("\x1b[B")):
+		c.scrollBy(-1)
+	case bytes.HasPrefix(data, []byte("\x1b[F")), bytes.HasPrefix(data, []byte("\x1b[4~")): // End
+		c.Follow()
+	case data[0] == ' ' || data[0] == 'p':
+		if v.paused {
+			c.Follow()
+		} else {
+			v.paused = true
+		}
+	case data[0] == '/':
+		v.paused = true
+		v.inSearch = true
+		v.input = v.input[:0]
+	case data[0] == 'n' && v.search != "":
+		c.findNext(true)
+	case data[0] == 'N' && v.search != "":
+		c.findNext(false)
+	case data[0] == 27 && len(data) == 1 && v.paused: // Esc
+		c.Follow()
+	default:
+		return false
+	}
+	c.tailOut.Hold = v.paused
+	return true
+}
+
+// TailMouse scrolls with the mouse wheel. Returns true if the event was used.
+func (c *Config) TailMouse() bool {
+	switch {
+	case c.ap.MouseWheelUp():
+		c.scrollBy(3)
+	case c.ap.MouseWheelDown():
+		c.scrollBy(-3)
+	default:
+		return false
+	}
+	c.tailOut.Hold = c.view.paused
+	return true
+}
+
+func (c *Config) findNext(backward bool) {
+	v := &c.view
+	if !v.paused {
+		v.match = c.tailOut.Lines.Len() // start from the bottom.
+	}
+	from := v.match + 1
+	if backward {
+		from = v.match - 1
+	}
+	if idx := c.tailOut.Lines.Find(v.search, from, backward); idx >= 0 {
+		v.match = idx
+		c.showMatch()
+	}
+}
+
+// DrawScrollback draws the paused/scrolled view of the tail area and its status line.
+func (c *Config) DrawScrollback() {
+	v := &c.view
+	lines := c.tailOut.Lines
+	added := lines.Total - v.lastTotal
+	dropped := lines.Dropped - v.lastDropped
+	// Growth at the bottom: the completed partial line is counted in added but not in Len.
+	grown := lines.Len() + dropped - v.lastLen
+	v.lastTotal, v.lastDropped, v.lastLen = lines.Total, lines.Dropped, lines.Len()
+	if !v.paused {
+		return
+	}
+	// New lines don't move the view (until the ring buffer wraps past it).
+	v.newLines += added
+	v.offset = max(0, min(v.offset+grown, lines.Len()-c.viewRows()))
+	v.match -= dropped
+	top, rows := c.TailTop(), c.viewRows()
+	last := lines.Len() - v.offset
+	for r := range rows {
+		i := last - rows + r
+		line := ""
+		if i >= 0 && i < lines.Len() {
+			line = c.highlightSearch(truncate(lines.At(i), c.ap.W), i == v.match)
+		}
+		c.ap.WriteAtStr(0, top+r, line)
+		c.ap.ClearEndOfLine()
+	}
+	var status string
+	switch {
+	case v.inSearch:
+		status = "/" + string(v.input)
+	default:
+		status = fmt.Sprintf("-- PAUSED -- line %d/%d, %d new | PgUp/PgDn, / search, n/N next/prev, space to follow",
+			max(0, last), lines.Len(), v.newLines)
+	}
+	c.ap.WriteAtStr(0, top+rows, tcolor.Inverse+truncate(status, c.ap.W)+tcolor.Reset)
+	c.ap.ClearEndOfLine()
+}
+
+// highlightSearch shows the occurrences of the search string in inverse (bold for the current match).
+func (c *Config) highlightSearch(line string, current bool) string {
+	search := c.view.search
+	if search == "" {
+		return line
+	}
+	on := tcolor.Inverse
+	if current {
+		on += tcolor.Bold
+	}
+	return strings.ReplaceAll(line, search, on+search+tcolor.Reset)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"testing"
+)
+
+func scrollbackLines(s *Scrollback) []string {
+	res := make([]string, 0, s.Len())
+	for i := range s.Len() {
+		res = append(res, s.At(i))
+	}
+	return res
+}
+
+func TestScrollback(t *testing.T) {
+	tests := []struct {
+		name        string
+		max         int
+		writes      []string
+		want        []string
+		total, drop int
+	}{
+		{"empty", 3, nil, []string{}, 0, 0},
+		{"disabled", 0, []string{"a\nb\n"}, []string{}, 0, 0},
+		{"lines", 3, []string{"a\nb\n"}, []string{"a", "b"}, 2, 0},
+		{"partial", 3, []string{"a\nb"}, []string{"a", "b"}, 1, 0},
+		{"split line", 3, []string{"a\nb", "c\r\nd\n"}, []string{"a", "bc", "d"}, 3, 0},
+		{"ansi cleaned", 3, []string{"\033[31mred\033[0m\n"}, []string{"red"}, 1, 0},
+		{"full", 3, []string{"1\n2\n3\n"}, []string{"1", "2", "3"}, 3, 0},
+		{"wrapped", 3, []string{"1\n2\n3\n4\n5\n"}, []string{"3", "4", "5"}, 5, 2},
+		{"wrapped with partial", 3, []string{"1\n2\n3\n4\n5"}, []string{"2", "3", "4", "5"}, 4, 1},
+		{"wrapped twice", 2, []string{"1\n2\n3\n", "4\n5\n6\n7\n"}, []string{"6", "7"}, 7, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Scrollback{Max: tt.max}
+			for _, w := range tt.writes {
+				if n, err := s.Write([]byte(w)); n != len(w) || err != nil {
+					t.Errorf("Write(%q) = %d, %v", w, n, err)
+				}
+			}
+			got := scrollbackLines(s)
+			if len(got) != len(tt.want) {
+				t.Fatalf("lines %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("lines %q, want %q", got, tt.want)
+					break
+				}
+			}
+			if s.Total != tt.total || s.Dropped != tt.drop {
+				t.Errorf("Total %d, Dropped %d, want %d, %d", s.Total, s.Dropped, tt.total, tt.drop)
+			}
+		})
+	}
+}
+
+func TestScrollbackFind(t *testing.T) {
+	s := &Scrollback{Max: 10}
+	_, _ = s.Write([]byte("foo 1\nbar\nfoo 2\nbaz\nfo"))
+	tests := []struct {
+		str      string
+		from     int
+		backward bool
+		want     int
+	}{
+		{"foo", 4, true, 2},
+		{"foo", 1, true, 0},
+		{"foo", 1, false, 2},
+		{"foo", 3, false, -1},
+		{"fo", 4, true, 4},
+		{"ba", 0, false, 1},
+		{"nope", 4, true, -1},
+		{"foo", -1, false, -1},
+	}
+	for _, tt := range tests {
+		if got := s.Find(tt.str, tt.from, tt.backward); got != tt.want {
+			t.Errorf("Find(%q, %d, %v) = %d, want %d", tt.str, tt.from, tt.backward, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package main
+
+import (
+	"os"
+)
+
+// No SIGUSR1/SIGUSR2 outside of unix.
+var quickControls = map[os.Signal]string{}
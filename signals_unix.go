@@ -0,0 +1,15 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// quickControls are the signals mapped to control commands: SIGUSR1 resets the countdown
+// (or elapsed time) and SIGUSR2 pauses/resumes it.
+var quickControls = map[os.Signal]string{
+	syscall.SIGUSR1: "reset",
+	syscall.SIGUSR2: "toggle-pause",
+}
@@ -0,0 +1,356 @@
+// Headless rendering: frames drawn into an in-memory screen instead of the terminal (tclock snapshot).
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"fortio.org/terminal/ansipixels"
+)
+
+// Cell is one character of the Screen with its colors and attributes (SGR parameters, e.g. "38;2;255;0;0").
+type Cell struct {
+	Rune   rune
+	Fg, Bg string
+	Attrs  string
+}
+
+func (c Cell) style() string {
+	return strings.Join(nonEmpty(c.Attrs, c.Fg, c.Bg), ";")
+}
+
+// nonEmpty returns the non empty parts.
+func nonEmpty(parts ...string) []string {
+	res := parts[:0:0]
+	for _, p := range parts {
+		if p != "" {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// Screen is a minimal terminal emulator for the output of the drawing code: cursor moves,
+// clears, colors and text (each rune one column wide). Other escape sequences are ignored.
+type Screen struct {
+	W, H  int
+	Cells [][]Cell
+	x, y  int
+	cur   Cell // current colors and attributes.
+	esc   []byte
+	utf   []byte
+}
+
+// NewScreen returns a blank w x h screen.
+func NewScreen(w, h int) *Screen {
+	s := &Screen{W: w, H: h, Cells: make([][]Cell, h)}
+	for y := range h {
+		s.Cells[y] = make([]Cell, w)
+	}
+	s.clear(0, 0, w, h)
+	return s
+}
+
+func (s *Screen) clear(x0, y0, x1, y1 int) {
+	for y := y0; y < y1; y++ {
+		for x := range s.W {
+			if (y == y0 && x < x0) || (y == y1-1 && x >= x1) {
+				continue
+			}
+			s.Cells[y][x] = Cell{Rune: ' ', Bg: s.cur.Bg}
+		}
+	}
+}
+
+// Write interprets the escape sequences and text written to the screen.
+func (s *Screen) Write(data []byte) (int, error) {
+	for _, b := range data {
+		switch {
+		case len(s.esc) > 0:
+			s.esc = append(s.esc, b)
+			s.escape()
+		case b == 0x1b:
+			s.esc = append(s.esc, b)
+		case b == '\r':
+			s.x = 0
+		case b == '\n':
+			s.y = min(s.y+1, s.H-1)
+		case b < 0x20:
+		default:
+			s.utf = append(s.utf, b)
+			if !utf8.FullRune(s.utf) {
+				continue
+			}
+			r, _ := utf8.DecodeRune(s.utf)
+			s.utf = s.utf[:0]
+			s.put(r)
+		}
+	}
+	return len(data), nil
+}
+
+func (s *Screen) put(r rune) {
+	if s.x < s.W && s.y < s.H {
+		cell := s.cur
+		cell.Rune = r
+		s.Cells[s.y][s.x] = cell
+	}
+	s.x++
+}
+
+// escape handles the sequence in s.esc once complete: CSI (ESC [ ... final byte) and OSC (ESC ] ... BEL or ST).
+func (s *Screen) escape() {
+	seq := s.esc
+	if len(seq) < 2 {
+		return
+	}
+	switch seq[1] {
+	case '[':
+		final := seq[len(seq)-1]
+		if len(seq) == 2 || final < 0x40 || final > 0x7e {
+			return
+		}
+		s.csi(string(seq[2:len(seq)-1]), final)
+	case ']':
+		if seq[len(seq)-1] != 0x07 && !strings.HasSuffix(string(seq), "\x1b\\") {
+			return
+		}
+	}
+	s.esc = s.esc[:0]
+}
+
+func (s *Screen) csi(params string, final byte) {
+	if strings.HasPrefix(params, "?") {
+		return // modes (sync, cursor visibility, mouse...).
+	}
+	args := strings.Split(params, ";")
+	arg := func(i, def int) int {
+		if i >= len(args) {
+			return def
+		}
+		n, err := strconv.Atoi(args[i])
+		if err != nil || n == 0 {
+			return def
+		}
+		return n
+	}
+	switch final {
+	case 'H':
+		s.y = min(max(arg(0, 1), 1), s.H) - 1
+		s.x = min(max(arg(1, 1), 1), s.W) - 1
+	case 'G':
+		s.x = min(max(arg(0, 1), 1), s.W) - 1
+	case 'C':
+		s.x = min(s.x+arg(0, 1), s.W-1)
+	case 'D':
+		s.x = max(s.x-arg(0, 1), 0)
+	case 'J':
+		switch arg(0, 0) {
+		case 0:
+			s.clear(s.x, s.y, s.W, s.H)
+		case 2, 3:
+			s.clear(0, 0, s.W, s.H)
+		}
+	case 'K':
+		if s.y < s.H {
+			s.clear(s.x, s.y, s.W, s.y+1)
+		}
+	case 'm':
+		s.sgr(args)
+	}
+}
+
+// sgr applies the Select Graphic Rendition parameters to the current cell style.
+func (s *Screen) sgr(args []string) {
+	for i := 0; i < len(args); i++ {
+		n, _ := strconv.Atoi(args[i])
+		switch {
+		case n == 0:
+			s.cur = Cell{}
+		case n == 38 || n == 48:
+			// 38;5;n or 38;2;r;g;b (48 for the background).
+			count := 3
+			if i+1 < len(args) && args[i+1] == "2" {
+				count = 5
+			}
+			end := min(i+count-1, len(args)-1)
+			color := strings.Join(args[i:end+1], ";")
+			if n == 38 {
+				s.cur.Fg = color
+			} else {
+				s.cur.Bg = color
+			}
+			i = end
+		case n == 39:
+			s.cur.Fg = ""
+		case n == 49:
+			s.cur.Bg = ""
+		case (n >= 30 && n <= 37) || (n >= 90 && n <= 97):
+			s.cur.Fg = args[i]
+		case (n >= 40 && n <= 47) || (n >= 100 && n <= 107):
+			s.cur.Bg = args[i]
+		case n >= 1 && n <= 9:
+			if !strings.Contains(";"+s.cur.Attrs+";", ";"+args[i]+";") {
+				s.cur.Attrs = strings.Join(nonEmpty(s.cur.Attrs, args[i]), ";")
+			}
+		case n >= 21 && n <= 29:
+			s.cur.Attrs = "" // good enough for our output.
+		}
+	}
+}
+
+// isBlack is true for the unset, black or very dark (SGR parameters) colors.
+func isBlack(color string) bool {
+	args := strings.Split(color, ";")
+	n, _ := strconv.Atoi(args[0])
+	switch {
+	case color == "":
+		return true
+	case len(args) == 5 && args[1] == "2": // 38;2;r;g;b
+		maxC := 0
+		for _, a := range args[2:] {
+			v, _ := strconv.Atoi(a)
+			maxC = max(maxC, v)
+		}
+		return maxC < 64
+	case len(args) == 3 && args[1] == "5": // 38;5;n
+		idx, _ := strconv.Atoi(args[2])
+		return idx == 0 || idx == 16 || (idx >= 232 && idx < 238)
+	default:
+		return n == 30 || n == 40
+	}
+}
+
+// visible is the character the cell looks like, ignoring the colors: half blocks and spaces drawn
+// with colors (images, discs) become the half or full blocks of their non black parts.
+func (c Cell) visible() rune {
+	var top, bottom bool
+	switch c.Rune {
+	case ' ':
+		top = !isBlack(c.Bg)
+		bottom = top
+	case '▄':
+		top, bottom = !isBlack(c.Bg), c.Fg == "" || !isBlack(c.Fg)
+	case '▀':
+		top, bottom = c.Fg == "" || !isBlack(c.Fg), !isBlack(c.Bg)
+	default:
+		return c.Rune
+	}
+	switch {
+	case top && bottom:
+		return '█'
+	case top:
+		return '▀'
+	case bottom:
+		return '▄'
+	default:
+		return ' '
+	}
+}
+
+// String returns the text of the screen, without colors and trailing spaces (colored
+// spaces and half blocks are shown as the blocks they look like).
+func (s *Screen) String() string {
+	var sb strings.Builder
+	for _, line := range s.Cells {
+		var l strings.Builder
+		for _, c := range line {
+			l.WriteRune(c.visible())
+		}
+		sb.WriteString(strings.TrimRight(l.String(), " "))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// ANSI returns the screen content with its colors, as lines that can be printed anywhere.
+func (s *Screen) ANSI() string {
+	var sb strings.Builder
+	for _, line := range s.Cells {
+		prev := ""
+		for _, c := range line {
+			if style := c.style(); style != prev {
+				sb.WriteString(ansipixels.Reset)
+				if style != "" {
+					sb.WriteString("\033[" + style + "m")
+				}
+				prev = style
+			}
+			sb.WriteRune(c.Rune)
+		}
+		if prev != "" {
+			sb.WriteString(ansipixels.Reset)
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// ParseSize parses WxH sizes (e.g. 80x24), or a single number for a square.
+func ParseSize(size string) (int, int, error) {
+	ws, hs, found := strings.Cut(strings.ToLower(size), "x")
+	if !found {
+		hs = ws
+	}
+	w, err1 := strconv.Atoi(ws)
+	h, err2 := strconv.Atoi(hs)
+	if err1 != nil || err2 != nil || w <= 0 || h <= 0 {
+		return 0, 0, fmt.Errorf("invalid size %q, should be like 80x24", size)
+	}
+	return w, h, nil
+}
+
+// Headless switches the Config's AnsiPixels to drawing into a w x h in-memory Screen.
+func (c *Config) Headless(w, h int) *Screen {
+	screen := NewScreen(w, h)
+	c.ap.Out = bufio.NewWriter(screen)
+	c.ap.W, c.ap.H = w, h
+	c.ap.GetSize = func() error { return nil }
+	return screen
+}
+
+// FrameString is the time shown at now: the clock, the countdown time left or the elapsed time.
+func (c *Config) FrameString(now time.Time) string {
+	switch {
+	case c.countDown:
+		return DurationString(max(0, c.end.Sub(c.Timer(now)).Round(time.Second)), c.seconds)
+	case c.countUp:
+		return DurationString(c.Timer(now).Sub(c.start), c.seconds)
+	default:
+		return now.Format(c.format)
+	}
+}
+
+// RenderFrame draws the (centered) clock as it is at now, on a cleared screen.
+func (c *Config) RenderFrame(now time.Time) {
+	c.now = now
+	c.ClearScreen()
+	c.DrawAt(-1, -1, TimeString(c.FrameString(now), false))
+	if c.help {
+		c.DrawHelp()
+	}
+	_ = c.ap.Out.Flush()
+}
+
+// Snapshot renders the frame at now to a w x h headless screen and writes it to out,
+// as text only (e.g. for golden files) or with colors.
+func (c *Config) Snapshot(out io.Writer, now time.Time, w, h int, textOnly bool) error {
+	if textOnly {
+		// Images in true color so the text shows them fully (the mono mode only keeps the bright pixels).
+		c.ap.TrueColor = true
+	}
+	screen := c.Headless(w, h)
+	c.RenderFrame(now)
+	content := screen.ANSI()
+	if textOnly {
+		content = screen.String()
+	}
+	_, err := io.WriteString(out, content)
+	return err
+}
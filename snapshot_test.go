@@ -0,0 +1,209 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"fortio.org/terminal/ansipixels"
+	"fortio.org/terminal/ansipixels/tcolor"
+)
+
+var update = flag.Bool("update", false, "update the testdata/*.golden files")
+
+// snapshotTime is the fixed instant of the golden snapshots.
+var snapshotTime = time.Date(2026, 1, 1, 12, 34, 56, 0, time.UTC)
+
+// testConfig is a Config with the default flag values, in true color, frozen at snapshotTime.
+func testConfig() *Config {
+	ap := ansipixels.NewAnsiPixels(0)
+	ap.TrueColor = true
+	ap.Background = tcolor.RGBColor{}
+	color, _ := tcolor.FromString("red")
+	disc, _ := tcolor.FromString(trueColorDiscDefault)
+	return &Config{
+		ap:               ap,
+		clock:            NewFakeClock(snapshotTime, 1),
+		color:            ap.ColorOutput.Foreground(color),
+		colorName:        "red",
+		colorDisc:        RGBColor(disc),
+		radius:           1.2,
+		aliasing:         0.8,
+		blendingFunction: ansipixels.BlendNSRGB,
+		blackBG:          tcolor.RGBColor{}.Background(),
+		seconds:          true,
+		format:           "3:04:05",
+	}
+}
+
+func TestSnapshotGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(c *Config)
+		w, h  int
+	}{
+		{"digital", func(*Config) {}, 60, 14},
+		{"analog", func(c *Config) { c.analog = true }, 60, 24},
+		{"aa", func(c *Config) { c.aa = true }, 60, 24},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := testConfig()
+			tt.setup(c)
+			var sb strings.Builder
+			if err := c.Snapshot(&sb, snapshotTime, tt.w, tt.h, true); err != nil {
+				t.Fatalf("Snapshot: %v", err)
+			}
+			golden := filepath.Join("testdata", tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(sb.String()), 0o644); err != nil {
+					t.Fatalf("WriteFile: %v", err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("ReadFile: %v (run with -update to create it)", err)
+			}
+			if got := sb.String(); got != string(want) {
+				t.Errorf("%s snapshot mismatch, got:\n%s\nwant:\n%s", tt.name, got, want)
+			}
+		})
+	}
+}
+
+func TestSnapshotLongText(t *testing.T) {
+	c := testConfig()
+	c.text = "$ " + strings.Repeat("x", 70) + " end"
+	var sb strings.Builder
+	if err := c.Snapshot(&sb, snapshotTime, 60, 14, true); err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	want := "$ " + strings.Repeat("x", 57) + "…"
+	if !strings.Contains(sb.String(), "\n"+want+"\n") {
+		t.Errorf("want the text cut to the 60 columns %q, got:\n%s", want, sb.String())
+	}
+}
+
+// screenLine writes data to a fresh w x 3 screen and returns its cells line y.
+func screenLine(data string, w, y int) []Cell {
+	s := NewScreen(w, 3)
+	_, _ = s.Write([]byte(data))
+	return s.Cells[y]
+}
+
+func text(cells []Cell) string {
+	var sb strings.Builder
+	for _, c := range cells {
+		sb.WriteRune(c.Rune)
+	}
+	return sb.String()
+}
+
+func TestScreenCursorAndErase(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		y    int
+		want string
+	}{
+		{"text", "ab\r\ncd", 1, "cd    "},
+		{"H", "\033[2;3Hx", 1, "  x   "},
+		{"H default", "abc\033[Hx", 0, "xbc   "},
+		{"H clamped", "\033[9;99Hx", 2, "     x"},
+		{"G", "abc\033[2Gx", 0, "axc   "},
+		{"C", "a\033[2Cx", 0, "a  x  "},
+		{"D", "abcd\033[2Dx", 0, "abxd  "},
+		{"D clamped", "ab\033[9Dx", 0, "xb    "},
+		{"J below", "abcdef\r\nghijkl\033[1;3H\033[J", 0, "ab    "},
+		{"J below next line", "abcdef\r\nghijkl\033[1;3H\033[0J", 1, "      "},
+		{"2J", "abcdef\033[2J", 0, "      "},
+		{"K", "abcdef\033[4G\033[K", 0, "abc   "},
+		{"modes ignored", "\033[?25l\033[?2026hab", 0, "ab    "},
+		{"osc bel skipped", "a\033]0;title\007b", 0, "ab    "},
+		{"osc st skipped", "a\033]8;;http://x\033\\b", 0, "ab    "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := text(screenLine(tt.data, 6, tt.y)); got != tt.want {
+				t.Errorf("line %d = %q, want %q", tt.y, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScreenSGR(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Cell
+	}{
+		{"truecolor fg", "\033[38;2;255;0;10mx", Cell{Rune: 'x', Fg: "38;2;255;0;10"}},
+		{"256 bg", "\033[48;5;16mx", Cell{Rune: 'x', Bg: "48;5;16"}},
+		{"both one sequence", "\033[38;5;1;48;2;1;2;3mx", Cell{Rune: 'x', Fg: "38;5;1", Bg: "48;2;1;2;3"}},
+		{"basic", "\033[31;44mx", Cell{Rune: 'x', Fg: "31", Bg: "44"}},
+		{"reset", "\033[1;31;44m\033[0mx", Cell{Rune: 'x'}},
+		{"empty reset", "\033[31m\033[mx", Cell{Rune: 'x'}},
+		{"default fg", "\033[31;44m\033[39mx", Cell{Rune: 'x', Bg: "44"}},
+		{"default bg", "\033[31;44m\033[49mx", Cell{Rune: 'x', Fg: "31"}},
+		{"attrs", "\033[1m\033[7m\033[1mx", Cell{Rune: 'x', Attrs: "1;7"}},
+		{"split utf-8", "\xe2\x96", Cell{Rune: ' '}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := screenLine(tt.data, 4, 0)[0]; got != tt.want {
+				t.Errorf("cell = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScreenSplitWrites(t *testing.T) {
+	s := NewScreen(4, 1)
+	data := []byte("\033[38;2;1;2;3m▄█")
+	for _, b := range data {
+		_, _ = s.Write([]byte{b})
+	}
+	want := Cell{Rune: '▄', Fg: "38;2;1;2;3"}
+	if got := s.Cells[0][0]; got != want {
+		t.Errorf("cell = %+v, want %+v", got, want)
+	}
+	if got := s.Cells[0][1].Rune; got != '█' {
+		t.Errorf("second rune = %q, want █", got)
+	}
+}
+
+func TestScreenString(t *testing.T) {
+	s := NewScreen(6, 2)
+	// Red on a black background half block, a gray space, a black space and text.
+	_, _ = s.Write([]byte("\033[38;2;255;0;0;48;2;0;0;0m▄\033[48;5;250m \033[48;5;0m \033[0mab\r\n\033[41m▄"))
+	want := "▄█ ab\n█\n"
+	if got := s.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		size    string
+		w, h    int
+		wantErr bool
+	}{
+		{"80x24", 80, 24, false},
+		{"120X40", 120, 40, false},
+		{"512", 512, 512, false},
+		{"", 0, 0, true},
+		{"80x", 0, 0, true},
+		{"0x10", 0, 0, true},
+		{"-5x10", 0, 0, true},
+		{"axb", 0, 0, true},
+	}
+	for _, tt := range tests {
+		w, h, err := ParseSize(tt.size)
+		if (err != nil) != tt.wantErr || w != tt.w || h != tt.h {
+			t.Errorf("ParseSize(%q) = %d, %d, %v, want %d, %d (error %v)", tt.size, w, h, err, tt.w, tt.h, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Control socket (-ctl) and its `tclock ctl` client.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fortio.org/log"
+)
+
+// replyTimeout is how long to wait for the main loop to execute a command.
+const replyTimeout = 5 * time.Second
+
+// DefaultSocketPath is the control socket used when -socket isn't specified: per user, in the temp directory.
+func DefaultSocketPath() string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("tclock-%d.sock", os.Getuid()))
+}
+
+// SendCommand passes the command to the main loop and waits for its reply.
+func SendCommand(commands chan<- Command, cmd Command) string {
+	select {
+	case commands <- cmd:
+	case <-time.After(replyTimeout):
+		return "error: busy"
+	}
+	select {
+	case reply := <-cmd.Reply:
+		return reply
+	case <-time.After(replyTimeout):
+		return "error: timeout"
+	}
+}
+
+// ListenControl listens on the unix socket path (replacing a stale one) for command lines,
+// each answered by a reply line. Returns the listener to close (which removes the socket).
+func ListenControl(path string, commands chan<- Command) (net.Listener, error) {
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("%s is in use by another tclock", path)
+	}
+	_ = os.Remove(path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	log.LogVf("Listening for control commands on %s", path)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			if err != nil {
+				log.Warnf("Control socket accept error: %v", err)
+				continue
+			}
+			go serveControl(conn, commands)
+		}
+	}()
+	return listener, nil
+}
+
+func serveControl(conn net.Conn, commands chan<- Command) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		reply := SendCommand(commands, ParseCommand(line))
+		if _, err := fmt.Fprintln(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+// Ctl is the `tclock ctl command args...` client: sends the command to the running instance
+// and prints the reply. Returns the exit code.
+func Ctl(path string, args []string) int {
+	if len(args) == 0 {
+		return log.FErrf("Usage: tclock ctl [-socket path] command [args], commands: %s", CommandsHelp)
+	}
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return log.FErrf("Unable to connect to tclock (started with -ctl?): %v", err)
+	}
+	defer conn.Close()
+	if _, err = fmt.Fprintln(conn, strings.Join(args, " ")); err != nil {
+		return log.FErrf("Error sending command: %v", err)
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if errors.Is(err, io.EOF) && args[0] == "quit" {
+		reply, err = "ok", nil // it exited before replying.
+	}
+	if err != nil {
+		return log.FErrf("Error reading reply: %v", err)
+	}
+	reply = strings.TrimSpace(reply)
+	fmt.Println(reply)
+	if strings.HasPrefix(reply, "error") {
+		return 1
+	}
+	return 0
+}
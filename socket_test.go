@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestControlSocket(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := &Config{clock: NewFakeClock(now, 1), format: "15:04"}
+	commands := make(chan Command)
+	done := make(chan struct{})
+	defer close(done)
+	go executeLoop(c, commands, done)
+	path := filepath.Join(t.TempDir(), "tclock.sock")
+	listener, err := ListenControl(path, commands)
+	if err != nil {
+		t.Fatalf("ListenControl: %v", err)
+	}
+	defer listener.Close()
+	if _, err = ListenControl(path, commands); err == nil {
+		t.Errorf("second ListenControl on the same socket should fail")
+	}
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+	replies := bufio.NewReader(conn)
+	tests := []struct {
+		line, want string
+	}{
+		{"set-countdown 10m", "ok"},
+		{"", ""}, // ignored, no reply: the next reply is for the next line.
+		{`text "Break time"`, "ok"},
+		{"status", `{"mode":"countdown","paused":false,"time":"10:00","left":"10m0s"`},
+		{"color nope", "error: "},
+		{"bogus", `error: unknown command "bogus"`},
+	}
+	for _, tt := range tests {
+		if _, err = fmt.Fprintln(conn, tt.line); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if tt.want == "" {
+			continue
+		}
+		reply, err := replies.ReadString('\n')
+		if err != nil || !strings.HasPrefix(reply, tt.want) {
+			t.Errorf("%q: reply %q, %v, want %q", tt.line, reply, err, tt.want)
+		}
+	}
+	if c.text != "Break time" {
+		t.Errorf("text %q, want Break time", c.text)
+	}
+}
@@ -0,0 +1,120 @@
+// Persisted state (-remember): clock placement and display toggles between runs, and the running countdown.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+
+	"fortio.org/log"
+)
+
+// State is what -remember saves on exit and restores on the next launch.
+type State struct {
+	// Placed is true when the clock was placed with a click, at X, Y relative (0-1)
+	// to the screen size so it survives terminal resizes.
+	Placed bool    `json:"placed"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	// Display toggles, nil when not saved yet (e.g. only a countdown was saved).
+	Analog     *bool `json:"analog,omitempty"`
+	AA         *bool `json:"aa,omitempty"`
+	Continuous *bool `json:"continuous,omitempty"`
+	// Countdown of the running tclock, for -statusline.
+	Countdown *CountdownState `json:"countdown,omitempty"`
+}
+
+// StatePath is the state file location, in the user's config directory.
+func StatePath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "state.json"), nil
+}
+
+// LoadState reads the state file. A missing file isn't an error and returns nil.
+func LoadState() (*State, error) {
+	path, err := StatePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil //nolint:nilnil // no state yet.
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s State
+	if err = json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	log.LogVf("Loaded state from %s: %+v", path, s)
+	return &s, nil
+}
+
+// Save writes the state file, creating the directory if needed.
+func (s *State) Save() error {
+	path, err := StatePath()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	log.LogVf("Saving state to %s: %s", path, data)
+	return os.WriteFile(path, append(data, '\n'), 0o644) //nolint:gosec // not a secret.
+}
+
+// Restore applies the saved toggles and placement (as the 1,1 origin mouse position the loop starts from).
+// Toggles set explicitly (command line, environment, config file or preset) take precedence.
+func (c *Config) Restore(s *State, explicit map[string]bool) {
+	if !explicit["analog"] && !explicit["aa"] && !explicit["c"] {
+		restore := func(value *bool, saved *bool) {
+			if saved != nil {
+				*value = *saved
+			}
+		}
+		restore(&c.analog, s.Analog)
+		restore(&c.aa, s.AA)
+		restore(&c.continuous, s.Continuous)
+	}
+	if s.Placed {
+		c.ap.Mx = int(math.Round(s.X * float64(c.ap.W)))
+		c.ap.My = int(math.Round(s.Y * float64(c.ap.H)))
+	}
+	c.state = s
+}
+
+// Place records the clock placement: x, y (1,1 origin) when placed with a click or none when following the mouse again.
+func (c *Config) Place(placed bool, x, y int) {
+	if c.state == nil {
+		return
+	}
+	c.state.Placed = placed
+	if placed {
+		c.state.X = float64(x) / float64(c.ap.W)
+		c.state.Y = float64(y) / float64(c.ap.H)
+	}
+}
+
+// SaveState saves the current toggles and placement, on exit.
+func (c *Config) SaveState() {
+	if c.state == nil {
+		return
+	}
+	analog, aa, continuous := c.analog, c.aa, c.continuous
+	c.state.Analog, c.state.AA, c.state.Continuous = &analog, &aa, &continuous
+	c.state.Countdown = nil // not running anymore.
+	if err := c.state.Save(); err != nil {
+		log.Warnf("Unable to save state: %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRestore(t *testing.T) {
+	tests := []struct {
+		name     string
+		state    string
+		explicit map[string]bool
+		analog   bool
+		aa       bool
+	}{
+		{"empty state keeps the flags", `{}`, nil, false, true},
+		{"countdown only keeps the flags", `{"countdown":{"start":"2026-01-01T00:00:00Z","end":"2026-01-01T00:05:00Z"}}`, nil, false, true},
+		{"saved toggles", `{"analog":true,"aa":false,"continuous":false}`, nil, true, false},
+		{"partial toggles", `{"analog":true}`, nil, true, true},
+		{"explicit flag wins", `{"analog":true,"aa":false}`, map[string]bool{"aa": true}, false, true},
+	}
+	for _, tt := range tests {
+		var s State
+		if err := json.Unmarshal([]byte(tt.state), &s); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		c := testConfig()
+		c.aa, c.continuous = true, true // e.g. from a preset.
+		c.Restore(&s, tt.explicit)
+		if c.analog != tt.analog || c.aa != tt.aa {
+			t.Errorf("%s: analog %v, aa %v, want %v, %v", tt.name, c.analog, c.aa, tt.analog, tt.aa)
+		}
+	}
+}
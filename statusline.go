@@ -0,0 +1,88 @@
+// One line status output for tmux, i3blocks, waybar, polybar... (-statusline and -follow).
+
+package main
+
+import (
+	"strings"
+	"time"
+
+	"fortio.org/log"
+)
+
+// statusBarWidth is the number of characters of the countdown progress bar.
+const statusBarWidth = 6
+
+// partialBlocks are the left aligned 1/8th to 7/8th blocks.
+var partialBlocks = []rune("▏▎▍▌▋▊▉")
+
+// ProgressBar draws the fraction (0-1) as a width characters bar with 1/8th character precision.
+func ProgressBar(fraction float64, width int) string {
+	eighths := int(max(0, min(1, fraction)) * float64(width*8))
+	full, part := eighths/8, eighths%8
+	var sb strings.Builder
+	sb.WriteRune('▕')
+	sb.WriteString(strings.Repeat("█", full))
+	if full < width {
+		if part > 0 {
+			sb.WriteRune(partialBlocks[part-1])
+		} else {
+			sb.WriteRune(' ')
+		}
+		sb.WriteString(strings.Repeat(" ", width-full-1))
+	}
+	sb.WriteRune('▏')
+	return sb.String()
+}
+
+// StatusLine is the compact status at now: the countdown time left and its progress bar
+// (when its start is known), or the time with the time zone.
+func (c *Config) StatusLine(now time.Time) string {
+	if !c.countDown {
+		return now.Format(c.format) + " " + now.Format("MST")
+	}
+	left := max(0, c.end.Sub(c.Timer(now)).Round(time.Second))
+	res := "⏱ " + DurationString(left, c.seconds)
+	if c.duration > 0 {
+		res += " " + ProgressBar(float64(left)/float64(c.duration), statusBarWidth)
+	}
+	if !c.pausedAt.IsZero() {
+		res += " ⏸"
+	}
+	return res
+}
+
+// CountdownState is the countdown of a running tclock -remember, saved for -statusline.
+type CountdownState struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Paused time.Time `json:"paused,omitzero"`
+}
+
+// SaveCountdown saves the current countdown (or its absence) in the -remember state file.
+func (c *Config) SaveCountdown() {
+	if c.state == nil {
+		return
+	}
+	c.state.Countdown = nil
+	if c.countDown {
+		c.state.Countdown = &CountdownState{Start: c.end.Add(-c.duration), End: c.end, Paused: c.pausedAt}
+	}
+	if err := c.state.Save(); err != nil {
+		log.Warnf("Unable to save state: %v", err)
+	}
+}
+
+// FollowState sets the countdown from the one saved by a running tclock -remember, if any.
+func (c *Config) FollowState() {
+	s, err := LoadState()
+	if err != nil {
+		log.Warnf("Ignoring invalid state file: %v", err)
+	}
+	c.countDown = s != nil && s.Countdown != nil
+	if !c.countDown {
+		return
+	}
+	c.end = s.Countdown.End
+	c.duration = s.Countdown.End.Sub(s.Countdown.Start)
+	c.pausedAt = s.Countdown.Paused
+}
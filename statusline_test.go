@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		fraction float64
+		width    int
+		want     string
+	}{
+		{0, 4, "▕    ▏"},
+		{1, 4, "▕████▏"},
+		{0.5, 4, "▕██  ▏"},
+		{1. / 32, 4, "▕▏   ▏"},
+		{0.3, 4, "▕█▏  ▏"},
+		{7. / 8, 1, "▕▉▏"},
+		{-1, 3, "▕   ▏"},
+		{2, 3, "▕███▏"},
+	}
+	for _, tt := range tests {
+		if got := ProgressBar(tt.fraction, tt.width); got != tt.want {
+			t.Errorf("ProgressBar(%v, %d) = %q, want %q", tt.fraction, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestStatusLine(t *testing.T) {
+	now := time.Date(2026, 1, 1, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name string
+		c    Config
+		want string
+	}{
+		{"clock", Config{format: TimeFormat(false, true)}, "3:04:05 UTC"},
+		{"clock 24h", Config{format: TimeFormat(true, false)}, "15:04 UTC"},
+		{"countdown", Config{countDown: true, seconds: true, end: now.Add(90 * time.Second)}, "⏱ 01:30"},
+		{
+			"countdown with bar",
+			Config{countDown: true, seconds: true, end: now.Add(3 * time.Minute), duration: 6 * time.Minute},
+			"⏱ 03:00 ▕███   ▏",
+		},
+		{
+			"paused",
+			Config{countDown: true, end: now.Add(time.Hour), pausedAt: now.Add(-time.Minute)},
+			"⏱ 01:01 ⏸",
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.c.StatusLine(now); got != tt.want {
+			t.Errorf("%s: StatusLine() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -1,79 +1,50 @@
 package main
 
 import (
-	"bufio"
-	"errors"
-	"fmt"
 	"io"
 	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"fortio.org/log"
 	"fortio.org/terminal"
 )
 
-func StdinTail(cfg *Config) int {
-	maxPoll := 100 * time.Millisecond
-	reader := terminal.NewTimeoutReader(os.Stdin, maxPoll)
-	var numStr string
-	ap := cfg.ap
-	var buf [4096]byte
-	ap.Out = bufio.NewWriter(os.Stdout)
-	defer ap.Out.Flush()
-	blink := false
-	var prevNow time.Time
-	prev := ""
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	for {
-		select {
-		case <-c:
-			log.LogVf("Interrupted, exiting")
-			return 0
-		default:
-		}
-		doDraw := cfg.breath
-		now := time.Now()
-		if cfg.countDown {
-			left := cfg.end.Sub(now).Round(time.Second)
-			if left < 0 {
-				ap.WriteString(fmt.Sprintf("\n\n\aTime's up reached at %s\r\n", now.Format(cfg.format)))
-				return 0
-			}
-			numStr = DurationString(left, cfg.seconds)
-		} else {
-			numStr = now.Format(cfg.format)
-		}
-		if numStr != prev {
-			doDraw = true
-		}
-		prev = numStr
-		now = now.Truncate(time.Second) // change only when seconds change
-		if now != prevNow && cfg.blinkEnabled {
-			blink = !blink
-			doDraw = true
-		}
-		prevNow = now
-		n, err := reader.Read(buf[:])
-		if err != nil {
-			if !errors.Is(err, io.EOF) {
-				return log.FErrf("Error reading stdin: %v", err)
-			}
-			log.Debugf("EOF on stdin")
-			time.Sleep(maxPoll) // EOF is continuous until there is more in the file, so avoid too tight loop.
-		}
-		if doDraw || n > 0 {
-			cfg.frame++
-			ap.StartSyncMode()
-			if n > 0 {
-				_, _ = ap.Out.Write(buf[:n])
-				ap.SaveCursorPos()
-			}
-			cfg.DrawAt(-1, -1, TimeString(numStr, blink))
-			ap.RestoreCursorPos()
-			ap.EndSyncMode()
-		}
+// NoInput replaces the terminal input when stdin is the tailed data rather than the keyboard:
+// reads just wait for the timeout so the main loop keeps its pace, and signals are still handled.
+type NoInput struct {
+	timeout time.Duration
+}
+
+func (n *NoInput) ChangeTimeout(timeout time.Duration) { n.timeout = timeout }
+func (n *NoInput) RawMode() error                      { return nil }
+func (n *NoInput) NormalMode() error                   { return nil }
+func (n *NoInput) StartDirect()                        {}
+func (n *NoInput) PrimeReadImmediate(_ []byte)         {}
+func (n *NoInput) ReadImmediate() (int, error)         { return 0, nil }
+func (n *NoInput) ReadBlocking(_ []byte) (int, error)  { return 0, io.EOF }
+
+func (n *NoInput) ReadWithTimeout(_ []byte) (int, error) {
+	time.Sleep(n.timeout)
+	return 0, nil
+}
+
+// StdinTail sets stdin as the tail source, for the same RawModeLoop as file tailing.
+// The keyboard (and mouse) input is then read from /dev/tty when available.
+func (c *Config) StdinTail() {
+	maxPoll := time.Millisecond
+	c.tail = terminal.NewSystemTimeoutReader(os.Stdin, maxPoll)
+	timeout := time.Duration(1e9 / c.ap.FPS)
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		log.Warnf("No keyboard input while tailing stdin: %v", err)
+		c.ap.SharedInput = &NoInput{timeout: timeout}
+		return
 	}
+	c.ap.SharedInput = terminal.NewInterruptReader(tty, 256, timeout)
+}
+
+// HasKeyboard is false when stdin is the tailed data and /dev/tty isn't available.
+func (c *Config) HasKeyboard() bool {
+	_, noInput := c.ap.SharedInput.(*NoInput)
+	return !noInput
 }
@@ -0,0 +1,145 @@
+// Tail mode helpers: per line timestamps and lines/second rate tracking.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// TailWriter writes the tailed data, optionally prefixing each line with its arrival time
+// (absolute or relative to the previous line) and counting lines for the rate sparkline.
+type TailWriter struct {
+	Out        io.Writer
+	Timestamps string // "" for none, "abs" or "rel".
+	Rate       LineRate
+	Lines      *Scrollback
+	Hold       bool      // when paused/scrolled back: only add to Lines, don't write to Out.
+	midLine    bool      // whether the last write ended without a newline (prefix already written).
+	prev       time.Time // arrival time of the previous line.
+}
+
+func (tw *TailWriter) prefix(now time.Time) string {
+	switch tw.Timestamps {
+	case "abs":
+		return now.Format("15:04:05.000 ")
+	case "rel":
+		var d time.Duration
+		if !tw.prev.IsZero() {
+			d = now.Sub(tw.prev)
+		}
+		return fmt.Sprintf("+%8.3fs ", d.Seconds())
+	default:
+		return ""
+	}
+}
+
+func (tw *TailWriter) write(p []byte) error {
+	_, _ = tw.Lines.Write(p)
+	if tw.Hold {
+		return nil
+	}
+	_, err := tw.Out.Write(p)
+	return err
+}
+
+// WriteLines writes buf, which arrived at now, to the underlying writer.
+func (tw *TailWriter) WriteLines(now time.Time, buf []byte) error {
+	for len(buf) > 0 {
+		if !tw.midLine {
+			if p := tw.prefix(now); p != "" {
+				if err := tw.write([]byte(p)); err != nil {
+					return err
+				}
+			}
+			tw.prev = now
+			tw.midLine = true
+		}
+		idx := bytes.IndexByte(buf, '\n')
+		if idx < 0 {
+			return tw.write(buf)
+		}
+		if err := tw.write(buf[:idx+1]); err != nil {
+			return err
+		}
+		tw.Rate.Add(now, 1)
+		tw.midLine = false
+		buf = buf[idx+1:]
+	}
+	return nil
+}
+
+// LineRate keeps the per second line counts of the last RateSeconds seconds.
+type LineRate struct {
+	counts [RateSeconds]int
+	last   int64 // unix second of the most recent bucket.
+}
+
+const RateSeconds = 64
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// advance moves the ring forward to now, zeroing the seconds without any lines.
+func (r *LineRate) advance(now time.Time) {
+	sec := now.Unix()
+	if r.last == 0 {
+		r.last = sec
+		return
+	}
+	for s := r.last + 1; s <= sec && s-r.last <= RateSeconds; s++ {
+		r.counts[s%RateSeconds] = 0
+	}
+	r.last = max(r.last, sec)
+}
+
+func (r *LineRate) Add(now time.Time, n int) {
+	r.advance(now)
+	r.counts[r.last%RateSeconds] += n
+}
+
+// ago returns the line count of ago seconds before the most recent bucket.
+func (r *LineRate) ago(ago int) int {
+	return r.counts[(r.last-int64(ago))%RateSeconds]
+}
+
+// Sparkline returns the last (up to RateSeconds) seconds of line counts, oldest first,
+// as a width wide string prefixed by the last full second's rate. Quiet seconds are blank.
+func (r *LineRate) Sparkline(now time.Time, width int) string {
+	r.advance(now)
+	label := fmt.Sprintf("%3d/s ", r.ago(1))
+	n := min(width-len(label), RateSeconds-1)
+	if n <= 0 {
+		return label[:max(width, 0)]
+	}
+	peak := 0
+	for i := range n {
+		peak = max(peak, r.ago(i))
+	}
+	var sb strings.Builder
+	sb.WriteString(strings.Repeat(" ", width-len(label)-n))
+	sb.WriteString(label)
+	for i := n - 1; i >= 0; i-- {
+		v := r.ago(i)
+		if v == 0 {
+			sb.WriteRune(' ')
+			continue
+		}
+		sb.WriteRune(sparks[v*(len(sparks)-1)/peak])
+	}
+	return sb.String()
+}
+
+// DrawRate draws the lines/second sparkline under the clock box (and text).
+func (c *Config) DrawRate(now time.Time) {
+	if c.tailOut == nil {
+		return
+	}
+	y := c.boxY + c.boxH
+	if c.text != "" {
+		y++
+	}
+	c.ap.WriteAtStr(c.boxX, y, c.tailOut.Rate.Sparkline(now, c.boxW))
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestSparkline(t *testing.T) {
+	base := time.Unix(1000, 0)
+	sec := func(s float64) time.Time { return base.Add(time.Duration(s * float64(time.Second))) }
+	tests := []struct {
+		name  string
+		adds  map[float64]int // seconds from base: lines.
+		now   float64
+		width int
+		want  string
+	}{
+		{"steady", map[float64]int{0: 8, 1: 4, 3.2: 2}, 3.5, 10, "  0/s █▄ ▂"},
+		{"padded", map[float64]int{0: 8, 1: 4, 3.2: 2}, 3.5, 12, "  0/s   █▄ ▂"},
+		{"last second rate", map[float64]int{0: 5}, 1, 8, "  5/s █ "},
+		{"label only", map[float64]int{0: 5}, 1, 6, "  5/s "},
+		{"truncated label", map[float64]int{0: 5}, 1, 3, "  5"},
+		{"quiet for long", map[float64]int{0: 5}, 100, 8, "  0/s   "},
+		{"empty", nil, 0, 8, "  0/s   "},
+	}
+	for _, tt := range tests {
+		var r LineRate
+		for _, s := range []float64{0, 1, 3.2} { // in order.
+			if n, found := tt.adds[s]; found {
+				r.Add(sec(s), n)
+			}
+		}
+		if got := r.Sparkline(sec(tt.now), tt.width); got != tt.want {
+			t.Errorf("%s: Sparkline() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTailWriter(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 12, 34, 56, 0, time.UTC)
+	tests := []struct {
+		timestamps string
+		hold       bool
+		want       string
+	}{
+		{"", false, "a\nbc\nd\n"},
+		{"rel", false, "+   0.000s a\n+   0.000s bc\n+   1.500s d\n"},
+		{"abs", false, "12:34:56.000 a\n12:34:56.000 bc\n12:34:57.500 d\n"},
+		{"", true, ""},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		tw := &TailWriter{Out: &out, Timestamps: tt.timestamps, Lines: &Scrollback{Max: 10}, Hold: tt.hold}
+		_ = tw.WriteLines(t0, []byte("a\nb"))
+		_ = tw.WriteLines(t0.Add(1500*time.Millisecond), []byte("c\nd\n"))
+		if got := out.String(); got != tt.want {
+			t.Errorf("%q (hold %v): output %q, want %q", tt.timestamps, tt.hold, got, tt.want)
+		}
+		if tw.Lines.Total != 3 || tw.Rate.ago(0) != 2 || tw.Rate.ago(1) != 1 {
+			t.Errorf("%q: %d lines, rate %d then %d, want 3 lines, 1 then 2", tt.timestamps, tw.Lines.Total,
+				tw.Rate.ago(1), tw.Rate.ago(0))
+		}
+	}
+}
@@ -1,12 +1,15 @@
 package main
 
 import (
+	"bufio"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"math"
 	"os"
+	"os/exec"
+	"slices"
 	"strings"
 	"time"
 
@@ -17,6 +20,7 @@ import (
 	"fortio.org/terminal"
 	"fortio.org/terminal/ansipixels"
 	"fortio.org/terminal/ansipixels/tcolor"
+	"golang.org/x/term"
 )
 
 func TimeString(numStr string, blink bool) string {
@@ -48,25 +52,69 @@ type Config struct {
 	blackBG     string          // ANSI sequence for the black background: either 16 basic (color 0) or RGB black (truecolor).
 	// whether to use linear blending for the color disc (instead of SRGB)
 	blendingFunction func(tcolor.RGBColor, tcolor.RGBColor, float64) tcolor.RGBColor
-	// Extra text (countdown)
-	text string
-	// In tail mode we stick the clock at the top right of the screen.
-	topRight bool
+	// Extra text (countdown), autoText when it's the default (countdown target or command)
+	text     string
+	autoText bool
+	// In tail mode the clock is pinned (top right by default) above or below the scrolling tail area.
+	tailMode bool
 	tail     io.Reader
+	// Pinned position of the clock (MousePosition for centered/mouse placement) and margin from the edges.
+	position Position
+	margin   int
+	// Tail output writer (timestamps and lines rate) and timestamps mode ("", "abs" or "rel").
+	tailOut    *TailWriter
+	timestamps string
+	// Scrollback size (lines) and paused/scrolled/search state of the tail area.
+	scrollback int
+	view       TailView
+	// Last drawn position and size of the (boxed) digital clock.
+	boxX, boxY, boxW, boxH int
 	// countdown mode
 	countDown          bool
 	end                time.Time
+	duration           time.Duration // initial countdown duration, for reset.
+	pausedAt           time.Time     // when the countdown or elapsed time was paused, zero if not paused.
 	extraNewLinesAtEnd bool
-	// time format
+	// elapsed time (count up) mode, eg. while running a command
+	countUp bool
+	start   time.Time
+	cmd     *exec.Cmd
+	// periodic command output (watch mode)
+	watch *Watch
+	// time format, 24 hours or am/pm
 	format string
+	h24    bool
 	// Mouse tracking flip flop (on click toggle or just off when using bounce or tail mode)
 	trackMouse bool
+	// In progress drag of the placed clock.
+	drag Drag
+	// Index in tcolor.BasicColorList of the current color, for cycling with right click, and its name.
+	colorIndex int
+	colorName  string
+	// Help overlay shown.
+	help bool
+	// Key bindings (-keys).
+	keys KeyMap
+	// Kiosk mode (-kiosk), nil otherwise.
+	kiosk *Kiosk
+	// Config file reloading.
+	reloader *Reloader
+	// Control commands (-ctl, -http and signals).
+	commands chan Command
+	// Remembered placement and toggles (-remember), nil if not remembering.
+	state *State
+	// -statusline shows the countdown saved in the state file by a running tclock -remember.
+	followState bool
 	// Blinking of the second
 	blinkEnabled bool
 	// Show seconds
 	seconds bool
 	// for analog, current time
 	now time.Time
+	// where now comes from: the real time or -fake-time/-speed.
+	clock Clock
+	// Output recording (-record), nil if not recording.
+	recorder *Recorder
 	// antialiased image based analog clock
 	aa bool
 	// continuous update at FPS instead of per second
@@ -87,14 +135,64 @@ func (c *Config) breathColor() tcolor.Color {
 	return c.blendingFunction(c.ap.Background, c.bcolor, alpha).Color()
 }
 
-func (c *Config) DrawAt(x, y int, str string) {
-	if c.aa {
-		c.DrawImage(c.now, c.seconds)
-		return
+// ClockArea is where the analog clocks are drawn: the whole screen or, in tail mode,
+// the pinned position (same height as the boxed digital clock).
+func (c *Config) ClockArea() (x, y, w, h int) {
+	if !c.tailMode {
+		return 0, 0, c.ap.W, c.ap.H
+	}
+	h = bignum.Height + 2
+	w = 2*h + 1
+	x, y = c.Pinned(w, h)
+	return x - w + 1, y - h + 1, w, h
+}
+
+// breathMax is the largest breathing disc radius factor.
+const breathMax = 1 + 9/15.
+
+// discRadius is the radius of the disc around the (boxed) width x height digits, 0 for no disc;
+// the largest one of the breathing effect when largest is true.
+func (c *Config) discRadius(width, height int, largest bool) int {
+	if c.colorDisc == (tcolor.RGBColor{}) {
+		return 0
+	}
+	mult := c.radius
+	switch {
+	case c.breath && largest:
+		mult *= breathMax
+	case c.breath:
+		mult *= (1 + float64(bounce(c.frame/7, 10))/15.)
+	}
+	// even radius is more symmetric
+	radius := 2 * int(math.Round(mult*float64(width)/4.))
+	if radius <= height { // so something is visible
+		radius = (2 * (height + 1)) / 2
 	}
-	if c.analog {
-		radius := min(c.ap.W/2, c.ap.H) - 1
-		c.DrawHands(c.ap.W/2, c.ap.H/2, radius, c.ap.Background, c.now, c.seconds)
+	return radius
+}
+
+// fitDisc caps the disc radius r so the whole disc fits within the margins of the screen.
+func (c *Config) fitDisc(r, height int) int {
+	limit := min(c.ap.H-2*c.margin, (c.ap.W-2*c.margin-1)/2) &^ 1
+	return max(min(r, limit), height+1)
+}
+
+func (c *Config) DrawAt(x, y int, str string) {
+	if c.aa || c.analog {
+		ax, ay, w, h := c.ClockArea()
+		c.boxX, c.boxY, c.boxW, c.boxH = ax, ay, w, h
+		if c.tailMode {
+			// No full screen clear in tail mode, so erase the previous hands.
+			for i := range h {
+				c.ap.WriteAtStr(ax, ay+i, strings.Repeat(" ", w))
+			}
+		}
+		if c.aa {
+			c.DrawImage(ax, ay, w, h, c.now, c.seconds)
+			return
+		}
+		radius := min(w/2, h) - 1
+		c.DrawHands(ax+w/2, ay+h/2, radius, c.ap.Background, c.now, c.seconds)
 		return
 	}
 	if c.debug {
@@ -116,9 +214,19 @@ func (c *Config) DrawAt(x, y int, str string) {
 		x = c.ap.W/2 + width/2
 		y = c.ap.H/2 + height/2
 	}
-	if c.topRight {
-		x = c.ap.W - 1
-		y = height - 1
+	radius := c.discRadius(width, height, false)
+	if c.position != MousePosition {
+		// Pin the whole disc, not just the digits, so it isn't cut by the screen edges.
+		// The disc spans 2r+1 columns and r lines (its last half line is empty).
+		fw, fh := width, height
+		if r := c.discRadius(width, height, true); r > 0 {
+			r = c.fitDisc(r, height)
+			radius = min(radius, r)
+			fw, fh = max(width, 2*r+1), max(height, r)
+		}
+		x, y = c.Pinned(fw, fh)
+		x -= (fw - width) / 2
+		y -= (fh - height) / 2
 	}
 	// We are in 0.0 coordinates, but on apple terminal for instance the mouse can go past the width (!)
 	// so clamp to valid screen dimensions.
@@ -126,23 +234,17 @@ func (c *Config) DrawAt(x, y int, str string) {
 	y = min(y, c.ap.H-1)
 	if c.bounce != 0 {
 		x = width - 1 + bounce(c.bounce, c.ap.W-width+1)
-		y = height - 1 + bounce(c.bounce, c.ap.H-height+1)
+		if !c.tailMode { // in tail mode we only bounce horizontally, within the reserved lines.
+			y = height - 1 + bounce(c.bounce, c.ap.H-height+1)
+		}
 	}
 	// draw from bottom right corner
 	x++
 	y++
 	x = max(x, width)
 	y = max(y, height)
-	if c.colorDisc != (tcolor.RGBColor{}) {
-		// even radius is more symmetric
-		mult := c.radius
-		if c.breath {
-			mult *= (1 + float64(bounce(c.frame/7, 10))/15.)
-		}
-		radius := 2 * int(math.Round(mult*float64(width)/4.))
-		if radius <= height { // so something is visible
-			radius = (2 * (height + 1)) / 2
-		}
+	c.boxX, c.boxY, c.boxW, c.boxH = x-width, y-height, width, height
+	if radius > 0 {
 		cx := x - width/2 - 1
 		cy := y - height/2 - 1
 		c.ap.DiscBlendFN(cx, cy, radius, c.ap.Background, c.colorDisc, c.aliasing, c.blendingFunction)
@@ -178,8 +280,13 @@ func (c *Config) DrawAt(x, y int, str string) {
 		c.ap.WriteAtStr(x-width, y-height+i, prefix+line+suffix)
 	}
 	if c.text != "" {
-		center := x - width/2 - c.ap.ScreenWidth(c.text)/2 - 1
-		c.ap.WriteAtStr(center, y+1, c.text)
+		text := c.text
+		if c.ap.ScreenWidth(text) > c.ap.W {
+			text = truncate(text, c.ap.W-1) + "…"
+		}
+		textWidth := c.ap.ScreenWidth(text)
+		center := max(0, min(x-width/2-textWidth/2-1, c.ap.W-textWidth))
+		c.ap.WriteAtStr(center, y+1, text)
 	}
 	// ap.MoveCursor(x-1, y-1)
 }
@@ -224,13 +331,63 @@ func DurationDDHHMM(duration time.Duration) string {
 	return fmt.Sprintf("%02d", minutes)
 }
 
+// TimeFormat is the time layout for the -24 and -no-seconds flags, e.g. 3:04:05.
+func TimeFormat(h24, seconds bool) string {
+	format := "3:04"
+	if h24 {
+		format = "15:04"
+	}
+	if seconds {
+		format += ":05"
+	}
+	return format
+}
+
+// CountdownText is the default text in countdown mode: the target time.
+func (c *Config) CountdownText() string {
+	toStr := c.end.Format(c.format)
+	if c.end.Sub(c.now) >= 24*time.Hour {
+		toStr = fmt.Sprintf("%s %s", c.end.Format("2006-01-02"), toStr)
+	}
+	extra := ""
+	if !c.h24 && c.end.Hour() >= 12 {
+		extra = " pm"
+	}
+	return "Countdown to " + toStr + extra
+}
+
 func (c *Config) Tail() *Config {
-	c.topRight = true
+	c.tailMode = true
+	if c.position == MousePosition {
+		c.position = TopRight
+	}
 	c.colorDisc = tcolor.RGBColor{}
 	c.boxed = true
 	return c
 }
 
+// ReservedLines is the number of lines (at the top or bottom of the screen) used by the clock in tail mode.
+func (c *Config) ReservedLines() int {
+	h := bignum.Height + 2 + c.margin + c.linesBelow() // box is forced in tail mode
+	return min(h, c.ap.H-1)
+}
+
+// SetupTailArea clears the screen and restricts scrolling (DECSTBM) to the lines not used by the clock
+// so the tailed output can't overwrite, nor be overwritten by, the clock. The cursor is left
+// (and saved) at the start of the scrolling region.
+func (c *Config) SetupTailArea() {
+	c.ClearScreen()
+	top := c.TailTop()
+	c.ap.WriteString(fmt.Sprintf("\033[%d;%dr", top+1, top+c.TailRows()))
+	c.ap.MoveCursor(0, top)
+	c.ap.SaveCursorPos()
+}
+
+// ResetScrollRegion restores the full screen scrolling region.
+func (c *Config) ResetScrollRegion() {
+	c.ap.WriteString("\033[r")
+}
+
 const (
 	trueColorDiscDefault   = "E0C020" // yellow/gold by default for true color
 	notrueColorDiscDefault = "FFFFFF" // gray scale for 256 colors mode as there are several gray levels but not many yellow
@@ -243,8 +400,12 @@ func Main() int { //nolint:funlen,gocognit,gocyclo,maintidx // we could split th
 		discDefault = notrueColorDiscDefault
 	}
 	cli.MinArgs = 0
-	cli.MaxArgs = 1
-	cli.ArgsHelp = " [digits:digits... or - for stdin tailing]\n" +
+	cli.MaxArgs = -1
+	cli.ArgsHelp = " [digits:digits... or - for stdin tailing or -- command args...]\n" +
+		"or config dump [flags] to print the effective configuration\n" +
+		"or ctl [-socket path] command [args] to control a running tclock -ctl: " + CommandsHelp + "\n" +
+		"or snapshot [flags] to print a single frame at -at time and -size (-plain for text only)\n" +
+		"or render [flags] -o file.png|gif|svg to save the clock as an image\n" +
 		"pass only flags will display current time; move mouse and click to place on screen"
 	fBounce := flag.Int("bounce", 0, "Bounce speed (0 is no bounce and normal mouse mode); 1 is fastest, 2 is slower, etc.")
 	f24 := flag.Bool("24", false, "Use 24-hour time format")
@@ -275,27 +436,110 @@ func Main() int { //nolint:funlen,gocognit,gocyclo,maintidx // we could split th
 	fAA := flag.Bool("aa", false, "Use antialiased image based analog clock")
 	fContinuous := flag.Bool("c", false, "Analog clock updates continuously instead of seconds ticks")
 	fFPS := flag.Float64("fps", 30, "Maximum frames per second (for -c)")
+	fRun := flag.String("run", "",
+		"Run the `command` (using sh -c) showing its output and the elapsed time (same as tclock -- command args...)")
+	fWatch := flag.String("watch", "", "Rerun the `command` (using sh -c) every -interval and show its output, highlighting changes")
+	fInterval := duration.Flag("interval", 2*time.Second, "`duration` between the end of a -watch command run and the next one")
+	fWatchTimeout := duration.Flag("watch-timeout", 0, "Stop a -watch command run (and the processes it started) after this `duration`, 0 for no limit")
+	fScrollback := flag.Int("scrollback", 10000, "Number of tailed `lines` to keep for scrolling back and searching")
+	fPosition := flag.String("position", "",
+		"Pin the clock to `position`: top-left, top-right, bottom-left, bottom-right or top-center (default is top-right in tail"+
+			" mode and mouse placement otherwise)")
+	fMargin := flag.Int("margin", 0, "Margin (in lines and columns) between the pinned clock and the screen edges")
+	fRemember := flag.Bool("remember", false,
+		"Restore the clock's last clicked placement and analog/aa/continuous toggles and save them on exit")
+	fKeys := flag.String("keys", "",
+		"Remap keys, `bindings` like quit=x,analog=mM (actions: quit, analog, continuous, help; ^C for Ctrl-C, empty to disable; "+
+			"space, p, /, n, N and Esc are reserved when tailing)")
+	fKiosk := flag.Bool("kiosk", false,
+		"Kiosk mode: ignore the keyboard (except for -kiosk-exit), the mouse and Ctrl-C, for unattended displays")
+	fKioskExit := flag.String("kiosk-exit", "", "Secret `sequence` to type to exit -kiosk mode (none by default)")
+	fTimestamps := flag.String("timestamps", "",
+		"In tail mode, prefix each line with its arrival time, `mode` is abs (absolute) or rel (relative to the previous line)")
+	fConfig := flag.String("config", "",
+		"Config `file` with defaults and presets (JSON), default is tclock/config.json in the user config directory (e.g. ~/.config)")
+	fPreset := flag.String("preset", "", "Use the named `preset` from the config file (flags still take precedence)")
+	fCtl := flag.Bool("ctl", false, "Accept control commands (see tclock ctl) on the -socket")
+	fSocket := flag.String("socket", "",
+		"Control socket `path`, default is tclock-UID.sock in the temp directory")
+	fHTTP := flag.String("http", "",
+		"Serve the control and status JSON API, and a page mirroring the clock, on `address` (e.g. :8080), without authentication")
+	fPlain := flag.Bool("plain", false,
+		"Print the time, or the countdown remaining time, as a text line every second instead of drawing (default when stdout isn't a terminal)")
+	fJSON := flag.Bool("json", false, "Like -plain but as JSON lines with now, target, remaining and state")
+	fStatusLine := flag.Bool("statusline", false,
+		"Print a one line status for status bars (tmux, waybar...) and exit: the countdown and its progress, or the time and zone")
+	fFollow := flag.Bool("follow", false, "With -statusline, print an updated status line every second instead of exiting")
+	fAt := flag.String("at", "", "Render the snapshot or image at this `date/time`, e.g. \"2026-01-01 12:34:56\" (default now)")
+	fSize := flag.String("size", "", "Screen `size` for snapshot (default 80x24) or image size in pixels for render (default 512)")
+	fOutput := flag.String("o", "", "Output `file` for render: .png or .gif (analog clock) or .svg (digital clock)")
+	fFrames := flag.Int("frames", 1, "Number of frames, one per second, of the animated gif render")
+	fFakeTime := flag.String("fake-time", "", "Start the clock at this `date/time` instead of the current time, e.g. \"2026-12-31 23:59:50\"")
+	fRecord := flag.String("record", "", "Record the output, with its timing, to this asciinema (.cast) `file`")
+	fSpeed := flag.String("speed", "", "Run the time faster (or slower) by this `factor`, e.g. 60x")
+	ctlCmd := len(os.Args) > 1 && os.Args[1] == "ctl"
+	if ctlCmd {
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
+	snapshot := len(os.Args) > 1 && os.Args[1] == "snapshot"
+	if snapshot {
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
+	render := len(os.Args) > 1 && os.Args[1] == "render"
+	if render {
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
+	configDump := len(os.Args) > 2 && os.Args[1] == "config" && os.Args[2] == "dump"
+	if configDump {
+		os.Args = append(os.Args[:1], os.Args[3:]...)
+	}
+	cli.EnvHelpFuncs = append(cli.EnvHelpFuncs, EnvHelp)
 	cli.Main()
-	format := "3:04"
-	if *f24 {
-		format = "15:04"
+	if err := SetFlagsFromEnv(ExplicitFlags()); err != nil {
+		return log.FErrf("Invalid environment: %v", err)
+	}
+	explicit := ExplicitFlags() // command line and environment.
+	socketPath := *fSocket
+	if socketPath == "" {
+		socketPath = DefaultSocketPath()
+	}
+	if ctlCmd {
+		return Ctl(socketPath, flag.Args())
+	}
+	configPath := *fConfig
+	if configPath == "" {
+		configPath = DefaultConfigPath()
+	}
+	configFile, err := LoadConfigFile(configPath, explicit["config"])
+	if err != nil {
+		return log.FErrf("Error reading config: %v", err)
+	}
+	if err = configFile.Apply(*fPreset, explicit); err != nil {
+		return log.FErrf("Config error: %v", err)
+	}
+	if configDump {
+		if err = DumpFlags(os.Stdout); err != nil {
+			return log.FErrf("Error dumping config: %v", err)
+		}
+		return 0
 	}
 	cfg := &Config{
-		boxed:              *fBox,
-		inverse:            *fInverse,
 		debug:              *fDebug,
-		breath:             *fBreath,
-		radius:             *fRadius,
-		fillBlack:          *fFillBlack,
-		aliasing:           *fAliasing,
-		format:             format,
-		seconds:            !*fNoSeconds,
 		bounceSpeed:        *fBounce,
-		blinkEnabled:       !*fNoBlink,
 		extraNewLinesAtEnd: true,
 		analog:             *fAnalog,
 		aa:                 *fAA,
 		continuous:         *fContinuous,
+		timestamps:         *fTimestamps,
+		scrollback:         *fScrollback,
+	}
+	if *fKiosk {
+		cfg.kiosk = &Kiosk{Exit: []byte(*fKioskExit)}
+	}
+	switch cfg.timestamps {
+	case "", "abs", "rel":
+	default:
+		return log.FErrf("Invalid -timestamps %q, should be abs or rel", cfg.timestamps)
 	}
 	if cfg.continuous && !cfg.analog && !cfg.aa {
 		cfg.aa = true
@@ -303,76 +547,144 @@ func Main() int { //nolint:funlen,gocognit,gocyclo,maintidx // we could split th
 	ap := ansipixels.NewAnsiPixels(*fFPS)
 	ap.TrueColor = *fTrueColor
 	cfg.ap = ap
-	colorDisc := *fColorDisc
-	if ap.TrueColor != truecolorDefault && colorDisc == discDefault {
-		// If we auto detected a change in true color mode, change the disc default too
-		// if it hasn't been set explicitly.
-		if ap.TrueColor {
-			colorDisc = trueColorDiscDefault
-		} else {
-			colorDisc = notrueColorDiscDefault
+	cfg.clock = RealClock{}
+	if *fFakeTime != "" || *fSpeed != "" {
+		start := time.Now()
+		if *fFakeTime != "" {
+			start, err = duration.ParseDateTime(start, *fFakeTime)
+			if err != nil {
+				return log.FErrf("Invalid -fake-time: %v", err)
+			}
 		}
+		speed := 1.
+		if *fSpeed != "" {
+			speed, err = ParseSpeed(*fSpeed)
+			if err != nil {
+				return log.FErrf("%v", err)
+			}
+		}
+		cfg.clock = NewFakeClock(start, speed)
 	}
-	if cfg.seconds {
-		cfg.format += ":05"
-	}
-	showText := *fText != "none"
-	if showText {
-		cfg.text = *fText
+	cfg.now = cfg.clock.Now()
+	if (snapshot || render) && *fAt != "" {
+		cfg.now, err = duration.ParseDateTime(cfg.now, *fAt)
+		if err != nil {
+			return log.FErrf("Invalid -at time: %v", err)
+		}
 	}
-	cfg.now = time.Now()
 	if *fCountdown > 0 {
 		cfg.countDown = true
 		cfg.end = cfg.now.Add(*fCountdown)
 	}
 	if *fUntil != "" {
 		cfg.countDown = true
-		var err error
 		cfg.end, err = duration.ParseDateTime(cfg.now, *fUntil)
 		if err != nil {
 			return log.FErrf("Invalid until time: %v", err)
 		}
 	}
-	if cfg.countDown && showText && cfg.text == "" {
-		toStr := cfg.end.Format(cfg.format)
-		if cfg.end.Sub(cfg.now) >= 24*time.Hour {
-			toStr = fmt.Sprintf("%s %s", cfg.end.Format("2006-01-02"), toStr)
+	cfg.duration = cfg.end.Sub(cfg.now)
+	// Text shown when -text isn't set: the countdown target or the command.
+	defaultText := ""
+	// Look settings, (re)applied from the flags at start and when the config is reloaded.
+	applyLook := func() error {
+		cfg.boxed = *fBox
+		cfg.inverse = *fInverse
+		cfg.breath = *fBreath
+		cfg.radius = *fRadius
+		cfg.fillBlack = *fFillBlack
+		cfg.aliasing = *fAliasing
+		cfg.blinkEnabled = !*fNoBlink
+		cfg.seconds = !*fNoSeconds
+		cfg.margin = max(0, *fMargin)
+		cfg.h24 = *f24
+		cfg.format = TimeFormat(*f24, cfg.seconds)
+		cfg.keys = DefaultKeyMap()
+		if err := cfg.keys.Parse(*fKeys); err != nil {
+			return fmt.Errorf("invalid -keys: %w", err)
 		}
-		extra := ""
-		if !*f24 && cfg.end.Hour() >= 12 {
-			extra = " pm"
+		if cfg.tail != nil { // on reload, checked below at start.
+			if err := cfg.keys.CheckTailKeys(); err != nil {
+				return fmt.Errorf("invalid -keys: %w", err)
+			}
 		}
-		cfg.text = "Countdown to " + toStr + extra
-	}
-	if *fLinearBlending {
-		cfg.blendingFunction = ansipixels.BlendLinear
-	} else {
-		cfg.blendingFunction = ansipixels.BlendNSRGB
-	}
-	if cfg.breath {
-		color, _ := tcolor.FromString(*fColor)
-		cfg.bcolor = RGBColor(color)
-	} else {
-		color, err := tcolor.FromString(*fColor)
+		var err error
+		cfg.position, err = ParsePosition(*fPosition)
 		if err != nil {
-			return log.FErrf("Color error: %v", err)
+			return fmt.Errorf("invalid -position: %w", err)
 		}
-		cfg.color = ap.ColorOutput.Foreground(color)
-	}
-	if *fColorBox != "" {
-		color, err := tcolor.FromString(*fColorBox)
-		if err != nil {
-			return log.FErrf("Color box error: %v", err)
+		cfg.text = ""
+		if *fText != "none" {
+			cfg.text = *fText
+		}
+		cfg.autoText = *fText == ""
+		if cfg.autoText {
+			cfg.text = defaultText
+			if cfg.countDown {
+				cfg.text = cfg.CountdownText()
+			}
+		}
+		if *fLinearBlending {
+			cfg.blendingFunction = ansipixels.BlendLinear
+		} else {
+			cfg.blendingFunction = ansipixels.BlendNSRGB
+		}
+		if cfg.breath {
+			color, _ := tcolor.FromString(*fColor)
+			cfg.bcolor = RGBColor(color)
+		} else {
+			color, err := tcolor.FromString(*fColor)
+			if err != nil {
+				return fmt.Errorf("color error: %w", err)
+			}
+			cfg.color = ap.ColorOutput.Foreground(color)
+			cfg.colorName = *fColor
+			cfg.colorIndex = slices.Index(tcolor.BasicColorList, tcolor.ColorMap[strings.ToLower(*fColor)])
+		}
+		cfg.colorBox = ""
+		if *fColorBox != "" {
+			color, err := tcolor.FromString(*fColorBox)
+			if err != nil {
+				return fmt.Errorf("color box error: %w", err)
+			}
+			cfg.colorBox = ap.ColorOutput.Foreground(color)
+			cfg.boxed = true // color box implies boxed
+		}
+		colorDisc := *fColorDisc
+		if ap.TrueColor != truecolorDefault && colorDisc == discDefault {
+			// If we auto detected a change in true color mode, change the disc default too
+			// if it hasn't been set explicitly.
+			if ap.TrueColor {
+				colorDisc = trueColorDiscDefault
+			} else {
+				colorDisc = notrueColorDiscDefault
+			}
+		}
+		cfg.colorDisc = tcolor.RGBColor{}
+		if colorDisc != "" {
+			color, err := tcolor.FromString(colorDisc)
+			if err != nil {
+				return fmt.Errorf("color disc error: %w", err)
+			}
+			cfg.colorDisc = RGBColor(color)
+		}
+		if cfg.tailMode {
+			cfg.Tail()
 		}
-		cfg.colorBox = ap.ColorOutput.Foreground(color)
-		cfg.boxed = true // color box implies boxed
+		return nil
 	}
-	if colorDisc != "" {
-		color, err := tcolor.FromString(colorDisc)
-		if err != nil {
-			return log.FErrf("Color disc error: %v", err)
+	if err = applyLook(); err != nil {
+		return log.FErrf("%v", err)
+	}
+	if configPath != "" {
+		cfg.reloader = &Reloader{Path: configPath, Preset: *fPreset, Explicit: explicit,
+			Apply: func(changed map[string]bool) error {
+				look := cfg.RuntimeLook()
+				err := applyLook()
+				cfg.KeepRuntimeLook(look, changed)
+				return err
+			},
 		}
-		cfg.colorDisc = RGBColor(color)
 	}
 	_ = ap.GetSize()
 	if cfg.ap.TrueColor {
@@ -381,119 +693,332 @@ func Main() int { //nolint:funlen,gocognit,gocyclo,maintidx // we could split th
 		cfg.blackBG = tcolor.Black.Background()
 	}
 	ap.Background = tcolor.RGBColor{}
+	if snapshot {
+		size := *fSize
+		if size == "" {
+			size = "80x24"
+		}
+		w, h, err := ParseSize(size)
+		if err != nil {
+			return log.FErrf("%v", err)
+		}
+		if err = cfg.Snapshot(os.Stdout, cfg.now, w, h, *fPlain); err != nil {
+			return log.FErrf("Error writing snapshot: %v", err)
+		}
+		return 0
+	}
+	if render {
+		size := *fSize
+		if size == "" {
+			size = "512"
+		}
+		w, h, err := ParseSize(size)
+		if err != nil {
+			return log.FErrf("%v", err)
+		}
+		if *fOutput == "" {
+			return log.FErrf("render needs an output file: -o clock.png, clock.gif or clock.svg")
+		}
+		if err = cfg.Render(*fOutput, w, h, cfg.now, *fFrames); err != nil {
+			return log.FErrf("Error rendering %s: %v", *fOutput, err)
+		}
+		return 0
+	}
 
-	if flag.NArg() == 1 {
+	cmdArgs := CommandArgs(flag.Args())
+	if *fRun != "" {
+		cmdArgs = []string{"sh", "-c", *fRun}
+	}
+	if len(cmdArgs) == 0 && flag.NArg() > 1 {
+		cli.ErrUsage("Only 1 argument expected unless after --, got %d", flag.NArg())
+		return 1
+	}
+	if len(cmdArgs) == 0 && flag.NArg() == 1 {
 		numStr := flag.Arg(0)
-		if numStr == "-" {
-			return StdinTail(cfg.Tail())
-		}
-		if len(numStr) == 0 || numStr[0] < '0' || numStr[0] > '9' {
+		switch {
+		case numStr == "-":
+			*fTail = "-"
+		case len(numStr) == 0 || numStr[0] < '0' || numStr[0] > '9':
 			cli.ErrUsage("No arguments, or <digits> or -")
 			return 1
+		default:
+			fmt.Println(TimeString(numStr, false))
+			return 0
 		}
-		fmt.Println(TimeString(numStr, false))
-		return 0
 	}
-	if *fTail != "" {
+	if *fTail == "-" {
+		cfg.Tail().StdinTail()
+		cfg.extraNewLinesAtEnd = false
+	} else if *fTail != "" {
 		cfg.Tail()
-		if *fTail == "-" {
-			return StdinTail(cfg)
-		}
 		file, err := os.Open(*fTail)
 		if err != nil {
 			return log.FErrf("Error opening tail file: %v", err)
 		}
 		defer file.Close() // pointless in main but makes AI happy.
 		cfg.tail = file
-		ap.SaveCursorPos()
 		cfg.extraNewLinesAtEnd = false
 	}
+	if len(cmdArgs) > 0 {
+		cfg.Tail()
+		if *fText == "" && !cfg.countDown {
+			defaultText = "$ " + ShellQuote(cmdArgs)
+			if *fRun != "" {
+				defaultText = "$ " + *fRun
+			}
+			cfg.text = defaultText
+		}
+		if err := cfg.StartCommand(cmdArgs); err != nil {
+			return log.FErrf("Error running %q: %v", cmdArgs, err)
+		}
+		defer cfg.KillCommand()
+		cfg.extraNewLinesAtEnd = false
+	}
+	if cfg.tail != nil {
+		if err := cfg.keys.CheckTailKeys(); err != nil {
+			return log.FErrf("invalid -keys: %v", err)
+		}
+	}
+	if *fWatch != "" {
+		cfg.Tail()
+		cfg.watch = NewWatch(*fWatch, *fInterval, *fWatchTimeout)
+	}
+	cfg.commands = make(chan Command)
+	QuickControls(cfg.commands)
+	if *fHTTP != "" {
+		listener, err := ListenHTTP(*fHTTP, cfg.commands)
+		if err != nil {
+			return log.FErrf("Error setting up the HTTP server: %v", err)
+		}
+		defer listener.Close()
+	}
+	if *fCtl {
+		listener, err := ListenControl(socketPath, cfg.commands)
+		if err != nil {
+			return log.FErrf("Error setting up the control socket: %v", err)
+		}
+		defer listener.Close()
+	}
+	plain := *fPlain || *fJSON || *fStatusLine
+	if plain && cfg.tailMode {
+		return log.FErrf("-plain, -json and -statusline only apply to the clock and countdowns, not to tail, commands or watch")
+	}
+	if *fStatusLine {
+		if *fUntil != "" && !*fFollow {
+			cfg.duration = 0 // start unknown: no progress bar.
+		}
+		cfg.followState = !cfg.countDown
+		if cfg.followState {
+			cfg.FollowState()
+		}
+		if !*fFollow {
+			fmt.Println(cfg.StatusLine(cfg.clock.Now()))
+			return 0
+		}
+		return cfg.PlainLoop(StatusLineOutput)
+	}
+	if !plain && !cfg.tailMode && !term.IsTerminal(int(os.Stdout.Fd())) {
+		log.LogVf("Stdout isn't a terminal, using -plain output")
+		plain = true
+	}
+	if plain {
+		mode := PlainOutput
+		if *fJSON {
+			mode = JSONOutput
+		}
+		return cfg.PlainLoop(mode)
+	}
+	if *fRecord != "" {
+		cfg.recorder, err = NewRecorder(*fRecord, ap.W, ap.H)
+		if err != nil {
+			return log.FErrf("Error creating the recording: %v", err)
+		}
+		defer cfg.recorder.Close()
+		ap.Out = bufio.NewWriter(io.MultiWriter(os.Stdout, cfg.recorder))
+	}
 	if err := ap.Open(); err != nil {
 		return log.FErrf("Error opening terminal: %v", err)
 	}
+	if cfg.kiosk != nil {
+		cfg.kiosk.Lock()
+	}
+	if cfg.reloader != nil {
+		cfg.reloader.Start()
+	}
 	defer func() {
 		if cfg.extraNewLinesAtEnd {
 			fmt.Fprintf(ap.Out, "\r\n\n\n\n")
 		}
+		if cfg.tailMode {
+			cfg.ResetScrollRegion()
+		}
 		ap.ShowCursor()
 		ap.MouseTrackingOff()
+		ap.MouseClickOff()
 		ap.EndSyncMode()
 		ap.Restore()
 	}()
-	if !cfg.topRight {
+	if cfg.tail == nil {
 		ap.HideCursor()
 		if !cfg.fillBlack {
 			ap.SyncBackgroundColor()
 		}
 		cfg.ClearScreen()
+	} else {
+		cfg.SetupTailArea()
+		if cfg.HasKeyboard() && cfg.kiosk == nil {
+			ap.MouseClickOn() // for the scrollback mouse wheel.
+		}
+	}
+	if *fRemember && !cfg.tailMode {
+		state, err := LoadState()
+		if err != nil {
+			log.Warnf("Ignoring invalid state file: %v", err)
+		}
+		if state == nil {
+			state = &State{}
+		}
+		cfg.Restore(state, ExplicitFlags()) // including the ones set by the config file.
+		cfg.SaveCountdown()
+		defer cfg.SaveState()
 	}
-	if (cfg.bounceSpeed <= 0) && !cfg.topRight && !cfg.analog {
+	if (cfg.bounceSpeed <= 0) && cfg.position == MousePosition && !cfg.analog && cfg.kiosk == nil {
 		ap.MouseTrackingOn()
-		cfg.trackMouse = true
+		cfg.trackMouse = cfg.state == nil || !cfg.state.Placed
 	}
 	return RawModeLoop(cfg)
 }
 
+// Quit on 'q', Ctrl-C or signal: exit status is 0 unless a countdown or command is aborted.
+func (c *Config) Quit() int {
+	if c.countDown {
+		c.ap.WriteAt(0, c.ap.H-3, "Countdown aborted at %s\r\n", c.now.Format(c.format))
+		return 1
+	}
+	if c.cmd != nil {
+		c.ap.WriteAt(0, c.ap.H-3, "Command aborted after %v\r\n", c.clock.Now().Sub(c.start).Round(time.Second))
+		return 1
+	}
+	return 0
+}
+
 //nolint:gocognit,funlen // yeah
 func RawModeLoop(cfg *Config) int {
 	var numStr string
 	ap := cfg.ap
 	var buf [4096]byte
 	writer := terminal.CRLFWriter{Out: ap.Out}
+	cfg.tailOut = &TailWriter{Out: &writer, Timestamps: cfg.timestamps, Lines: &Scrollback{Max: cfg.scrollback}}
 	blink := false
 	var prevNow time.Time
 	// TODO: how to get initial mouse position?
 	x, y := ap.Mx, ap.My
 	frame := 0
 	prev := ""
-	ap.OnResize = func() error {
-		cfg.ClearScreen()
+	redraw := func() error {
+		if cfg.recorder != nil {
+			cfg.recorder.Resize(cfg.ap.W, cfg.ap.H)
+		}
 		cfg.ap.StartSyncMode()
+		switch {
+		case cfg.tail == nil:
+			cfg.ap.HideCursor()
+			cfg.ClearScreen()
+		case cfg.view.paused:
+			cfg.SetupTailArea()
+			cfg.DrawScrollback()
+		default:
+			cfg.Follow()
+		}
 		cfg.DrawAt(-1, -1, TimeString(prev, false))
+		cfg.ap.RestoreCursorPos()
 		cfg.ap.EndSyncMode()
 		return nil
 	}
+	ap.OnResize = redraw
+	if cfg.kiosk != nil {
+		ap.OnResize = cfg.kiosk.Resized
+	}
 	for {
 		_, err := ap.ReadOrResizeOrSignalOnce()
+		if errors.Is(err, terminal.ErrSignal) {
+			return cfg.Quit()
+		}
 		if err != nil {
 			return 1
 		}
+		if cfg.kiosk != nil {
+			if cfg.kiosk.Input(ap.Data) {
+				return cfg.Quit()
+			}
+			ap.Data = nil
+			if cfg.kiosk.Settled(time.Now()) {
+				_ = redraw()
+			}
+		}
 		doDraw := cfg.breath || cfg.continuous
-		// Exit on 'q' or Ctrl-C but with status error in countdown mode.
-		if len(ap.Data) > 0 {
-			switch ap.Data[0] {
-			case 'q', 3:
-				if cfg.countDown {
-					ap.WriteAt(0, ap.H-3, "Countdown aborted at %s\r\n", cfg.now.Format(cfg.format))
-					return 1
+		if cfg.reloader != nil && cfg.reloader.Check(time.Now()) {
+			if err := cfg.reloader.Reload(); err != nil {
+				log.Warnf("Error reloading config, keeping the current one: %v", err)
+			}
+			_ = redraw()
+			doDraw = true
+		}
+		for done := false; !done; {
+			select {
+			case cmd := <-cfg.commands:
+				before := cfg.commandState()
+				reply, quit := cfg.Execute(cmd)
+				cmd.Reply <- reply
+				if quit {
+					return cfg.Quit()
 				}
-				return 0
-			case 'a', 'A':
+				if cfg.commandState() == before {
+					continue // status, errors and no-ops: no flicker nor tail view reset.
+				}
+				cfg.SaveCountdown()
+				_ = redraw() // the text (and thus the tail area) may have changed.
+				doDraw = true
+			default:
+				done = true
+			}
+		}
+		if cfg.tail != nil && (cfg.TailMouse() || (len(ap.Data) > 0 && cfg.TailKey(ap.Data))) {
+			doDraw = true
+			ap.Data = nil
+		}
+		if len(ap.Data) > 0 {
+			switch cfg.keys[ap.Data[0]] {
+			case QuitAction:
+				return cfg.Quit()
+			case AnalogAction:
 				cfg.aa = !cfg.aa
 				cfg.analog = !cfg.aa
 				doDraw = true
-			case 'c', 'C':
+			case ContinuousAction:
 				cfg.continuous = !cfg.continuous
 				doDraw = true
-			default:
+			case HelpAction:
+				cfg.ToggleHelp()
+				doDraw = true
+			case NoAction:
 			}
 		}
-		// Click to place the time at the mouse position (or switch back to move with mouse).
-		if ap.LeftClick() && ap.MouseRelease() {
-			cfg.trackMouse = !cfg.trackMouse
+		if cfg.tail == nil {
+			var changed bool
+			x, y, changed = cfg.Mouse(x, y)
+			doDraw = doDraw || changed
 		}
-		cfg.now = time.Now()
+		cfg.now = cfg.clock.Now()
 		if cfg.countDown {
-			left := cfg.end.Sub(cfg.now).Round(time.Second)
+			left := cfg.end.Sub(cfg.Timer(cfg.now)).Round(time.Second)
 			if left < 0 {
 				ap.WriteAt(0, ap.H-2, "\aTime's up reached at %s\r\n", cfg.now.Format(cfg.format))
 				cfg.extraNewLinesAtEnd = false
 				return 0
 			}
-			numStr = DurationString(left, cfg.seconds)
-		} else {
-			numStr = cfg.now.Format(cfg.format)
 		}
+		numStr = cfg.FrameString(cfg.now)
 		if numStr != prev {
 			doDraw = true
 		}
@@ -517,12 +1042,22 @@ func RawModeLoop(cfg *Config) int {
 			x, y = ap.Mx, ap.My
 			doDraw = true
 		}
+		if cfg.watch != nil && cfg.watch.Update(time.Now()) {
+			doDraw = true
+		}
 		n := 0
 		if cfg.tail != nil {
 			n, err = cfg.tail.Read(buf[:])
 			if err != nil && !errors.Is(err, io.EOF) {
 				return log.FErrf("Error reading tail file: %v", err)
 			}
+			if err != nil && cfg.cmd != nil {
+				if cfg.view.paused {
+					cfg.Follow()
+				}
+				cfg.DrawAt(-1, -1, TimeString(numStr, false))
+				return cfg.CommandDone(&writer)
+			}
 		}
 		if doDraw || n > 0 {
 			cfg.frame++
@@ -531,12 +1066,22 @@ func RawModeLoop(cfg *Config) int {
 				cfg.ClearScreen()
 			}
 			if n > 0 {
-				_, _ = writer.Write(buf[:n])
+				_ = cfg.tailOut.WriteLines(cfg.clock.Now(), buf[:n])
 				ap.SaveCursorPos()
 			}
 			// -1 to switch to ansipixels 0,0 origin (from 1,1 terminal origin)
 			// also means 0,0 is now -1,-1 and will center the time until the mouse is moved.
 			cfg.DrawAt(x-1, y-1, TimeString(numStr, blink))
+			if cfg.tail != nil {
+				cfg.DrawRate(cfg.now)
+				cfg.DrawScrollback()
+			}
+			if cfg.watch != nil {
+				cfg.DrawWatch(time.Now()) // real time, for the refresh countdown.
+			}
+			if cfg.help {
+				cfg.DrawHelp()
+			}
 			ap.RestoreCursorPos()
 			ap.EndSyncMode()
 		}
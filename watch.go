@@ -0,0 +1,157 @@
+// watch(1) style periodic command output panel under the clock.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"fortio.org/log"
+	"fortio.org/terminal/ansipixels"
+	"fortio.org/terminal/ansipixels/tcolor"
+)
+
+type watchResult struct {
+	lines []string
+	err   error
+	done  time.Time
+}
+
+// Watch reruns Command every Interval (from the end of the previous run, like watch(1) does)
+// in the background and keeps the current and previous output to highlight the differences.
+// It is scheduled with the real time, even with -fake-time or -speed, as it runs an external command.
+type Watch struct {
+	Command  string
+	Interval time.Duration
+	Timeout  time.Duration // of each run, 0 for no limit.
+	next     time.Time     // when to start the next run.
+	running  bool
+	results  chan watchResult
+	prev     []string
+	cur      watchResult
+}
+
+func NewWatch(command string, interval, timeout time.Duration) *Watch {
+	return &Watch{
+		Command:  command,
+		Interval: interval,
+		Timeout:  timeout,
+		results:  make(chan watchResult, 1),
+	}
+}
+
+func (w *Watch) run() {
+	ctx := context.Background()
+	if w.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.Timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, "sh", "-c", w.Command) //nolint:gosec // running the user's command is the point.
+	// On timeout, stop the shell and the commands it started, not just sh.
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error { return killProcessGroup(cmd) }
+	cmd.WaitDelay = time.Second
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		err = fmt.Errorf("timed out after %v", w.Timeout)
+	}
+	// Remove colors/escape codes and tabs so we can compare and truncate by runes.
+	clean, _ := ansipixels.AnsiClean(out)
+	text := strings.ReplaceAll(strings.TrimRight(string(clean), "\n"), "\t", "        ")
+	w.results <- watchResult{lines: strings.Split(text, "\n"), err: err, done: time.Now()}
+}
+
+// Update starts the next run when it's due at (real time) now and returns true when new output is available.
+func (w *Watch) Update(now time.Time) bool {
+	select {
+	case res := <-w.results:
+		w.running = false
+		w.prev = w.cur.lines
+		w.cur = res
+		w.next = res.done.Add(w.Interval)
+		log.Debugf("Watch %q done: %d lines, err %v", w.Command, len(res.lines), res.err)
+		return true
+	default:
+	}
+	if w.running || now.Before(w.next) {
+		return false
+	}
+	w.running = true
+	go w.run()
+	return false
+}
+
+// header is the "Every 2s: command" line with the refresh countdown or run status.
+func (w *Watch) header(now time.Time) string {
+	status := "running..."
+	if !w.running {
+		status = fmt.Sprintf("next refresh in %v", w.next.Sub(now).Round(time.Second))
+	}
+	if w.cur.err != nil {
+		status += " (" + w.cur.err.Error() + ")"
+	}
+	return fmt.Sprintf("Every %v: %s\n%s", w.Interval, w.Command, status)
+}
+
+// truncate returns str cut to at most width runes.
+func truncate(str string, width int) string {
+	runes := []rune(str)
+	return string(runes[:max(0, min(len(runes), width))])
+}
+
+// highlight returns line truncated to width with the runes that differ from prev in inverse video.
+func highlight(line, prev string, width int) string {
+	cur, old := []rune(truncate(line, width)), []rune(prev)
+	var sb strings.Builder
+	inverse := false
+	for i, r := range cur {
+		changed := i >= len(old) || old[i] != r
+		if changed != inverse {
+			inverse = changed
+			if inverse {
+				sb.WriteString(tcolor.Inverse)
+			} else {
+				sb.WriteString(tcolor.Reset)
+			}
+		}
+		sb.WriteRune(r)
+	}
+	if inverse {
+		sb.WriteString(tcolor.Reset)
+	}
+	return sb.String()
+}
+
+// DrawWatch draws the watch header next to the clock (on the widest side) and the latest output
+// in the tail area.
+func (c *Config) DrawWatch(now time.Time) {
+	w := c.watch
+	headerX, headerWidth := 0, c.boxX-1
+	if right := c.boxX + c.boxW + 1; c.ap.W-right > headerWidth {
+		headerX, headerWidth = right, c.ap.W-right
+	}
+	for i, line := range strings.Split(w.header(now), "\n") {
+		c.ap.WriteAtStr(headerX, c.BandTop()+i, truncate(line, headerWidth))
+	}
+	top := c.TailTop()
+	// Only highlight once there is a previous run to compare with.
+	compare := w.prev != nil
+	for i, line := range w.cur.lines {
+		y := top + i
+		if y >= top+c.TailRows() {
+			break
+		}
+		prev := line
+		if compare {
+			prev = ""
+			if i < len(w.prev) {
+				prev = w.prev[i]
+			}
+		}
+		c.ap.WriteAtStr(0, y, highlight(line, prev, c.ap.W))
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// waitWatch runs the watch until its first result is available.
+func waitWatch(t *testing.T, w *Watch) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !w.Update(time.Now()) {
+		if time.Now().After(deadline) {
+			t.Fatalf("no watch result for %q", w.Command)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no sh")
+	}
+	w := NewWatch("printf 'a\\tb\\n\\033[31mred\\033[0m\\n'", time.Hour, 0)
+	waitWatch(t, w)
+	if w.cur.err != nil || len(w.cur.lines) != 2 || w.cur.lines[0] != "a        b" || w.cur.lines[1] != "red" {
+		t.Errorf("lines %q, err %v", w.cur.lines, w.cur.err)
+	}
+	if next := time.Until(w.next); next < 59*time.Minute {
+		t.Errorf("next run in %v, want the 1h interval from the real time", next)
+	}
+}
+
+func TestWatchTimeout(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no sh")
+	}
+	// The sleep is a child of sh, the process group is killed so the run doesn't wait for it.
+	w := NewWatch("echo started; sleep 10; echo done", time.Hour, 100*time.Millisecond)
+	start := time.Now()
+	waitWatch(t, w)
+	if elapsed := time.Since(start); elapsed > 900*time.Millisecond {
+		t.Errorf("timed out run took %v", elapsed)
+	}
+	if w.cur.err == nil || w.cur.lines[0] != "started" {
+		t.Errorf("lines %q, err %v, want the output so far and a timeout error", w.cur.lines, w.cur.err)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		line, prev string
+		width      int
+		want       string
+	}{
+		{"abc", "abc", 10, "abc"},
+		{"abc", "abd", 10, "ab\033[7mc\033[0m"},
+		{"abcd", "ab", 10, "ab\033[7mcd\033[0m"},
+		{"xbcx", "abca", 3, "\033[7mx\033[0mbc"},
+		{"", "abc", 10, ""},
+	}
+	for _, tt := range tests {
+		if got := highlight(tt.line, tt.prev, tt.width); got != tt.want {
+			t.Errorf("highlight(%q, %q, %d) = %q, want %q", tt.line, tt.prev, tt.width, got, tt.want)
+		}
+	}
+}